@@ -0,0 +1,133 @@
+/*
+ * This file is part of MinIO Operator
+ * Copyright (C) 2021, MinIO, Inc.
+ *
+ * This code is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License, version 3,
+ * as published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License, version 3,
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/minio/kubectl-minio/cmd/helpers"
+	"github.com/minio/kubectl-minio/cmd/resources"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	operatorv1 "github.com/minio/operator/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	restartDesc = `
+'restart' command restarts the MinIO pods of a tenant one at a time, the operator only restarts the next pod once the
+tenant keeps write quorum without it`
+	restartExample = `  kubectl minio tenant restart tenant1 --namespace tenant1-ns`
+)
+
+type restartCmd struct {
+	out        io.Writer
+	errOut     io.Writer
+	output     bool
+	tenantOpts resources.TenantOptions
+}
+
+func newTenantRestartCmd(out io.Writer, errOut io.Writer) *cobra.Command {
+	c := &restartCmd{out: out, errOut: errOut}
+
+	cmd := &cobra.Command{
+		Use:     "restart <string>",
+		Short:   "Restart the MinIO pods of an existing tenant one at a time",
+		Long:    restartDesc,
+		Example: restartExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.validate(args); err != nil {
+				return err
+			}
+			c.tenantOpts.Name = args[0]
+			klog.Info("restart tenant command started")
+			err := c.run()
+			if err != nil {
+				klog.Warning(err)
+				return err
+			}
+			return nil
+		},
+	}
+	cmd = helpers.DisableHelp(cmd)
+	f := cmd.Flags()
+	f.StringVarP(&c.tenantOpts.NS, "namespace", "n", helpers.DefaultNamespace, "namespace scope for this request")
+	f.BoolVarP(&c.output, "output", "o", false, "dry run this command and generate requisite yaml")
+	return cmd
+}
+
+func (r *restartCmd) validate(args []string) error {
+	if args == nil {
+		return errors.New("provide the name of the tenant, e.g. 'kubectl minio tenant restart tenant1'")
+	}
+	if len(args) != 1 {
+		return errors.New("restart command requires a single argument, e.g. 'kubectl minio tenant restart tenant1'")
+	}
+	if args[0] == "" {
+		return errors.New("provide the name of the tenant, e.g. 'kubectl minio tenant restart tenant1'")
+	}
+	return nil
+}
+
+// run requests a rolling restart of the tenant by setting spec.restartRequestedAt to the current time
+func (r *restartCmd) run() error {
+	// Create operator client
+	client, err := helpers.GetKubeOperatorClient()
+	if err != nil {
+		return err
+	}
+
+	t, err := client.MinioV2().Tenants(r.tenantOpts.NS).Get(context.Background(), r.tenantOpts.Name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if t.RestartRequested() {
+		return fmt.Errorf("Tenant '%s/%s' is already restarting, requested at %s", t.Namespace, t.Name, t.Spec.RestartRequestedAt)
+	}
+
+	now := v1.Now()
+	t.Spec.RestartRequestedAt = &now
+
+	if !r.output {
+		return r.restartTenant(client, t)
+	}
+	o, err := yaml.Marshal(&t)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(o))
+	return nil
+}
+
+func (r *restartCmd) restartTenant(client *operatorv1.Clientset, t *miniov2.Tenant) error {
+	if helpers.Ask(fmt.Sprintf("Restart all MinIO pods of Tenant '%s/%s' one at a time?", t.ObjectMeta.Name, t.ObjectMeta.Namespace)) {
+		if _, err := client.MinioV2().Tenants(t.Namespace).Update(context.Background(), t, v1.UpdateOptions{}); err != nil {
+			return err
+		}
+		fmt.Printf(Bold(fmt.Sprintf("\nRestarting Tenant '%s/%s'\n\n", t.ObjectMeta.Name, t.ObjectMeta.Namespace)))
+	} else {
+		fmt.Printf(Bold(fmt.Sprintf("\nAborting Tenant restart\n\n")))
+	}
+	return nil
+}
@@ -42,21 +42,33 @@ import (
 	prominformers "github.com/prometheus-operator/prometheus-operator/pkg/client/informers/externalversions"
 	promclientset "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
 	apiextension "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	certapi "k8s.io/client-go/kubernetes/typed/certificates/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 )
 
 // version provides the version of this operator
 var version = "DEVELOPMENT.GOGET"
 
+// leaderElectionLeaseName is the name of the Lease the operator replicas compete for
+const leaderElectionLeaseName = "minio-operator-lock"
+
 var (
 	masterURL     string
 	kubeconfig    string
 	hostsTemplate string
 	checkVersion  bool
 
+	leaderElect                 bool
+	leaderElectionNamespace     string
+	leaderElectionLeaseDuration time.Duration
+	leaderElectionRenewDeadline time.Duration
+	leaderElectionRetryPeriod   time.Duration
+
 	onlyOneSignalHandler = make(chan struct{})
 	shutdownSignals      = []os.Signal{os.Interrupt, syscall.SIGTERM}
 )
@@ -68,6 +80,11 @@ func init() {
 	flag.StringVar(&masterURL, "master", "", "the address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster")
 	flag.StringVar(&hostsTemplate, "hosts-template", "", "the go template to use for hostname formatting of name fields (StatefulSet, CIService, HLService, Ellipsis, Domain)")
 	flag.BoolVar(&checkVersion, "version", false, "print version")
+	flag.BoolVar(&leaderElect, "leader-elect", true, "use a Lease so only one replica of the operator reconciles tenants at a time")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "", "namespace of the leader election Lease, defaults to the namespace of the operator")
+	flag.DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "how long non-leader replicas wait before trying to acquire an expired Lease")
+	flag.DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 10*time.Second, "how long the leader keeps retrying to renew the Lease before giving up leadership")
+	flag.DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 2*time.Second, "how long replicas wait between attempts to acquire or renew the Lease")
 }
 
 func main() {
@@ -118,11 +135,16 @@ func main() {
 		klog.Errorf("Error building Prometheus clientset: %v", err.Error())
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building dynamic clientset: %s", err.Error())
+	}
+
 	namespace, isNamespaced := os.LookupEnv("WATCHED_NAMESPACE")
 
 	ctx := context.Background()
 	var caContent []byte
-	operatorCATLSCert, err := kubeClient.CoreV1().Secrets(miniov2.GetNSFromFile()).Get(ctx, "operator-ca-tls", metav1.GetOptions{})
+	operatorCATLSCert, err := kubeClient.CoreV1().Secrets(miniov2.GetNSFromFile()).Get(ctx, cluster.OperatorCATLSSecretName, metav1.GetOptions{})
 	// if custom ca.crt is not present in kubernetes secrets use the one stored in the pod
 	if err != nil {
 		caContent = miniov2.GetPodCAFromFile()
@@ -162,7 +184,7 @@ func main() {
 		promInformerFactory = prominformers.NewSharedInformerFactory(promClient, time.Second*30)
 	}
 
-	mainController := cluster.NewController(kubeClient, controllerClient, *certClient, promClient,
+	mainController := cluster.NewController(kubeClient, controllerClient, *certClient, promClient, dynamicClient,
 		kubeInformerFactory.Apps().V1().StatefulSets(),
 		kubeInformerFactory.Apps().V1().Deployments(),
 		kubeInformerFactory.Batch().V1().Jobs(),
@@ -174,15 +196,90 @@ func main() {
 	go kubeInformerFactory.Start(stopCh)
 	go minioInformerFactory.Start(stopCh)
 
-	if err = mainController.Start(2, stopCh); err != nil {
-		klog.Fatalf("Error running mainController: %s", err.Error())
+	// Every replica serves the webhooks, only the leader reconciles tenants
+	mainController.StartWebhookServer()
+
+	run := func(stopCh <-chan struct{}) {
+		if err := mainController.Run(2, stopCh); err != nil {
+			klog.Fatalf("Error running mainController: %s", err.Error())
+		}
+	}
+
+	electionDone := make(chan struct{})
+	if leaderElect {
+		go func() {
+			runLeaderElection(kubeClient, stopCh, run)
+			close(electionDone)
+		}()
+	} else {
+		run(stopCh)
+		close(electionDone)
 	}
 
 	<-stopCh
+	// give the leader a chance to release the Lease so another replica takes over right away
+	<-electionDone
 	klog.Info("Shutting down the MinIO Operator")
 	mainController.Stop()
 }
 
+// runLeaderElection campaigns for the operator Lease and calls run once this replica becomes the leader. The
+// replica exits if it ever loses the Lease, so a restarted pod starts from a clean state.
+func runLeaderElection(kubeClient kubernetes.Interface, stopCh <-chan struct{}, run func(stopCh <-chan struct{})) {
+	id, err := os.Hostname()
+	if err != nil {
+		klog.Fatalf("Error getting hostname for leader election: %s", err.Error())
+	}
+
+	namespace := leaderElectionNamespace
+	if namespace == "" {
+		namespace = miniov2.GetNSFromFile()
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      leaderElectionLeaseName,
+			Namespace: namespace,
+		},
+		Client: kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: id,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   leaderElectionLeaseDuration,
+		RenewDeadline:   leaderElectionRenewDeadline,
+		RetryPeriod:     leaderElectionRetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				run(ctx.Done())
+			},
+			OnStoppedLeading: func() {
+				select {
+				case <-stopCh:
+					klog.Infof("%s released the leader lease", id)
+				default:
+					klog.Fatalf("%s lost the leader lease", id)
+				}
+			},
+			OnNewLeader: func(identity string) {
+				if identity != id {
+					klog.Infof("%s is the leader", identity)
+				}
+			},
+		},
+	})
+}
+
 // setupSignalHandler registered for SIGTERM and SIGINT. A stop channel is returned
 // which is closed on one of these signals. If a second signal is caught, the program
 // is terminated with exit code 1.
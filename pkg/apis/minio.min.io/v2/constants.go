@@ -46,6 +46,13 @@ const DefaultImagePullPolicy = corev1.PullIfNotPresent
 // CSRNameSuffix specifies the suffix added to Tenant name to create a CSR
 const CSRNameSuffix = "-csr"
 
+// TenantFinalizer is added to every Tenant so the Operator can apply the reclaim policy and clean up cluster scoped
+// resources before the Tenant goes away
+const TenantFinalizer = "minio.min.io/tenant-finalizer"
+
+// PausedAnnotation stops the Operator from changing the Tenant when set to "true", same as `spec.paused`
+const PausedAnnotation = "min.io/paused"
+
 // MinIO Related Constants
 
 // MinIOCertPath is the path where all MinIO certs are mounted
@@ -70,6 +77,12 @@ const Revision = "min.io/revision"
 // MinIOPort specifies the default Tenant port number.
 const MinIOPort = 9000
 
+// MinIOLivenessPath is the path of the liveness check of MinIO
+const MinIOLivenessPath = "/minio/health/live"
+
+// MinIOReadinessPath is the path of the readiness check of MinIO
+const MinIOReadinessPath = "/minio/health/ready"
+
 // MinIOPortLoadBalancerSVC specifies the default Service port number for the load balancer service.
 const MinIOPortLoadBalancerSVC = 80
 
@@ -133,6 +146,12 @@ const ConsoleServiceTLSPortName = "https-console"
 // ConsoleServiceNameSuffix specifies the suffix added to Tenant service name to create a service for console
 const ConsoleServiceNameSuffix = "-ui"
 
+// IngressNameSuffix specifies the suffix added to Tenant name to create the Ingress routing to the tenant services
+const IngressNameSuffix = "-ingress"
+
+// HTTPRouteNameSuffix specifies the suffix added to the MinIO and Console service names to create their HTTPRoutes
+const HTTPRouteNameSuffix = "-route"
+
 // ConsoleName specifies the default container name for Console
 const ConsoleName = "-console"
 
@@ -325,3 +344,14 @@ const monitoringIntervalEnv = "MONITORING_INTERVAL"
 
 // DefaultMonitoringInterval is how often we run monitoring on tenants
 const DefaultMonitoringInterval = 3
+
+const monitoringWorkersEnv = "MONITORING_WORKERS"
+
+// DefaultMonitoringWorkers is how many tenants we monitor at the same time
+const DefaultMonitoringWorkers = 10
+
+// DefaultUpgradeHealthWindow is how long the tenant health is watched after an upgrade if not set in the spec
+const DefaultUpgradeHealthWindow = 5 * time.Minute
+
+// DefaultDriveOfflineThreshold is how long a drive must be offline before it's replaced if not set in the spec
+const DefaultDriveOfflineThreshold = 10 * time.Minute
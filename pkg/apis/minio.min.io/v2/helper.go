@@ -23,6 +23,7 @@ import (
 	"compress/gzip"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -84,6 +85,9 @@ const (
 	WebhookCRDConversaion   = WebhookAPIVersion + "/crd-conversion"
 )
 
+// OperatorMetricsPath is the path the Operator serves its Prometheus metrics on, next to the webhook APIs
+const OperatorMetricsPath = "/metrics"
+
 type hostsTemplateValues struct {
 	StatefulSet string
 	CIService   string
@@ -98,11 +102,13 @@ var (
 	tenantConsoleImageOnce sync.Once
 	tenantKesImageOnce     sync.Once
 	monitoringIntervalOnce sync.Once
+	monitoringWorkersOnce  sync.Once
 	k8sClusterDomain       string
 	tenantMinIOImage       string
 	tenantConsoleImage     string
 	tenantKesImage         string
 	monitoringInterval     int
+	monitoringWorkers      int
 )
 
 // GetPodCAFromFile assumes the operator is running inside a k8s pod and extract the
@@ -240,7 +246,7 @@ func ExtractTar(filesToExtract []string, basePath, tarFileName string) error {
 		_ = tarFile.Close()
 	}()
 
-	tr := tar.NewReader(tarFile)
+	var r io.Reader = tarFile
 	if strings.HasSuffix(tarFileName, ".gz") {
 		gz, err := gzip.NewReader(tarFile)
 		if err != nil {
@@ -249,9 +255,15 @@ func ExtractTar(filesToExtract []string, basePath, tarFileName string) error {
 		defer func() {
 			_ = gz.Close()
 		}()
-		tr = tar.NewReader(gz)
+		r = gz
 	}
+	return ExtractTarReader(filesToExtract, basePath, r)
+}
 
+// ExtractTarReader extracts all files from the list `filesToExtract` out of the uncompressed tar stream `r` and puts
+// the files in the `basePath` location
+func ExtractTarReader(filesToExtract []string, basePath string, r io.Reader) error {
+	tr := tar.NewReader(r)
 	var success = len(filesToExtract)
 	for {
 		header, err := tr.Next()
@@ -322,6 +334,14 @@ func (t *Tenant) EnsureDefaults() *Tenant {
 		t.Spec.Mountpath = MinIOVolumeMountPath
 	}
 
+	if t.Spec.ReclaimPolicy == "" {
+		t.Spec.ReclaimPolicy = ReclaimPolicyRetain
+	}
+
+	if t.Spec.UpgradeStrategy == "" {
+		t.Spec.UpgradeStrategy = UpgradeStrategyInPlace
+	}
+
 	if t.Spec.Subpath == "" {
 		t.Spec.Subpath = MinIOVolumeSubPath
 	}
@@ -426,16 +446,95 @@ func (t *Tenant) GenBearerToken(accessKey, secretKey string) string {
 	return token
 }
 
+// PoolStatusIndex returns the index of the status of the pool, -1 is returned if the pool is not in the status yet.
+// The status of a pool being migrated to a new StatefulSet no longer belongs to the pool.
+func (t *Tenant) PoolStatusIndex(pool *Pool) int {
+	for i := range t.Status.Pools {
+		if t.Status.Pools[i].MigratingTo == "" && t.isPoolStatefulSet(pool, t.Status.Pools[i].SSName) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isPoolStatefulSet returns true if the StatefulSet name belongs to the pool, including legacy zone names and the
+// names of migrated pools
+func (t *Tenant) isPoolStatefulSet(pool *Pool, ssName string) bool {
+	if ssName == t.PoolStatefulsetName(pool) || ssName == t.LegacyStatefulsetName(pool) {
+		return true
+	}
+	_, ok := t.poolMigration(pool, ssName)
+	return ok
+}
+
+// poolMigration returns the number of migrations the pool went through to run on the StatefulSet, false is returned
+// if the StatefulSet is not one the pool was migrated to
+func (t *Tenant) poolMigration(pool *Pool, ssName string) (int, bool) {
+	migration := strings.TrimPrefix(ssName, t.PoolStatefulsetName(pool)+"-m")
+	if migration == ssName {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(migration, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// ServerPools returns the pools the MinIO servers are started with, the pools in the spec along with the pools removed
+// from the spec or migrated to a new StatefulSet that are still being decommissioned, in the order they were added to
+// the tenant
+func (t *Tenant) ServerPools() []Pool {
+	pools, _ := t.serverPools()
+	return pools
+}
+
+// ServerPoolStatefulSets returns the name of the StatefulSet of each pool returned by ServerPools
+func (t *Tenant) ServerPoolStatefulSets() []string {
+	_, ssNames := t.serverPools()
+	return ssNames
+}
+
+func (t *Tenant) serverPools() ([]Pool, []string) {
+	var pools []Pool
+	var ssNames []string
+	inStatus := make(map[int]bool)
+	for _, poolStatus := range t.Status.Pools {
+		inSpec := false
+		for i := range t.Spec.Pools {
+			if !inStatus[i] && poolStatus.MigratingTo == "" && t.isPoolStatefulSet(&t.Spec.Pools[i], poolStatus.SSName) {
+				pools = append(pools, t.Spec.Pools[i])
+				ssNames = append(ssNames, poolStatus.SSName)
+				inStatus[i] = true
+				inSpec = true
+				break
+			}
+		}
+		if !inSpec && (poolStatus.State == PoolDecommissioning || (poolStatus.MigratingTo != "" && poolStatus.State != PoolDecommissioned)) {
+			pools = append(pools, Pool{
+				Name:             poolStatus.Name,
+				Servers:          poolStatus.Servers,
+				VolumesPerServer: poolStatus.VolumesPerServer,
+			})
+			ssNames = append(ssNames, poolStatus.SSName)
+		}
+	}
+	// pools not deployed yet go last
+	for i := range t.Spec.Pools {
+		if !inStatus[i] {
+			pools = append(pools, t.Spec.Pools[i])
+			ssNames = append(ssNames, t.PoolStatefulsetName(&t.Spec.Pools[i]))
+		}
+	}
+	return pools, ssNames
+}
+
 // MinIOHosts returns the domain names in ellipses format created for current Tenant
 func (t *Tenant) MinIOHosts() (hosts []string) {
+	pools, ssNames := t.serverPools()
 	// Create the ellipses style URL
-	for pi, pool := range t.Spec.Pools {
-		// determine the proper statefulset name
-		ssName := t.PoolStatefulsetName(&pool)
-		if len(t.Status.Pools) > pi {
-			ssName = t.Status.Pools[pi].SSName
-		}
-
+	for i, pool := range pools {
+		ssName := ssNames[i]
 		if pool.Servers == 1 {
 			hosts = append(hosts, fmt.Sprintf("%s-%s.%s.%s.svc.%s", ssName, "0", t.MinIOHLServiceName(), t.Namespace, GetClusterDomain()))
 		} else {
@@ -454,11 +553,12 @@ func (t *Tenant) TemplatedMinIOHosts(hostsTemplate string) (hosts []string) {
 		return hosts
 	}
 	var max, index int32
+	pools, ssNames := t.serverPools()
 	// Create the ellipses style URL
-	for _, pool := range t.Spec.Pools {
+	for i, pool := range pools {
 		max = max + pool.Servers
 		data := hostsTemplateValues{
-			StatefulSet: t.MinIOStatefulSetNameForPool(&pool),
+			StatefulSet: ssNames[i],
 			CIService:   t.MinIOCIServiceName(),
 			HLService:   t.MinIOHLServiceName(),
 			Ellipsis:    genEllipsis(int(index), int(max)-1),
@@ -550,6 +650,62 @@ func (t *Tenant) HasPrometheusSMEnabled() bool {
 	return t.Spec.PrometheusOperator != nil
 }
 
+// MinIOIngressHosts returns the hostnames routed to the MinIO service from outside of the cluster, the bucket
+// subdomains are routed as well when bucket DNS is enabled
+func (t *Tenant) MinIOIngressHosts() []string {
+	if t.Spec.Ingress == nil || t.Spec.Ingress.MinIOHost == "" {
+		return nil
+	}
+	hosts := []string{t.Spec.Ingress.MinIOHost}
+	if t.S3BucketDNS() {
+		hosts = append(hosts, "*."+t.Spec.Ingress.MinIOHost)
+	}
+	return hosts
+}
+
+// ConsoleIngressHost returns the hostname routed to the Console service from outside of the cluster
+func (t *Tenant) ConsoleIngressHost() string {
+	if t.Spec.Ingress == nil || !t.HasConsoleEnabled() {
+		return ""
+	}
+	return t.Spec.Ingress.ConsoleHost
+}
+
+// MinIOIngressTLSSecret returns the name of the TLS secret the Ingress terminates TLS for the MinIO hostnames with
+func (t *Tenant) MinIOIngressTLSSecret() string {
+	if t.Spec.Ingress == nil {
+		return ""
+	}
+	if t.Spec.Ingress.MinIOTLSSecret != nil {
+		return t.Spec.Ingress.MinIOTLSSecret.Name
+	}
+	for _, secret := range t.Spec.ExternalCertSecret {
+		if isTLSSecretType(secret.Type) {
+			return secret.Name
+		}
+	}
+	return ""
+}
+
+// ConsoleIngressTLSSecret returns the name of the TLS secret the Ingress terminates TLS for the Console hostname with
+func (t *Tenant) ConsoleIngressTLSSecret() string {
+	if t.Spec.Ingress == nil {
+		return ""
+	}
+	if t.Spec.Ingress.ConsoleTLSSecret != nil {
+		return t.Spec.Ingress.ConsoleTLSSecret.Name
+	}
+	if t.HasConsoleEnabled() && t.Spec.Console.ExternalCertSecret != nil && isTLSSecretType(t.Spec.Console.ExternalCertSecret.Type) {
+		return t.Spec.Console.ExternalCertSecret.Name
+	}
+	return ""
+}
+
+// isTLSSecretType returns true for the secret types that store the certificate and key as `tls.crt` and `tls.key`
+func isTLSSecretType(secretType string) bool {
+	return secretType == string(corev1.SecretTypeTLS) || secretType == "cert-manager.io/v1alpha2"
+}
+
 // HasConsoleEnabled checks if the console has been enabled by the user
 func (t *Tenant) HasConsoleEnabled() bool {
 	return t.Spec.Console != nil
@@ -624,20 +780,21 @@ func (t *Tenant) MinIOServerEndpoint() string {
 	return u.String()
 }
 
-// MinIOHealthCheck check MinIO cluster health
-func (t *Tenant) MinIOHealthCheck() bool {
+// MinIOHealthCheck check MinIO cluster health, the MinIO certificate is verified against rootCAs. An error is returned
+// if MinIO is unreachable or reports it is not healthy.
+func (t *Tenant) MinIOHealthCheck(rootCAs *x509.CertPool) error {
 	// Keep TLS config.
 	tlsConfig := &tls.Config{
 		// Can't use SSLv3 because of POODLE and BEAST
 		// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 		// Can't use TLSv1.1 because of RC4 cipher usage
-		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: true, // FIXME: use trusted CA
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    rootCAs,
 	}
 
 	req, err := http.NewRequest(http.MethodGet, t.MinIOServerEndpoint()+"/minio/health/cluster", nil)
 	if err != nil {
-		return false
+		return err
 	}
 
 	httpClient := &http.Client{
@@ -664,19 +821,25 @@ func (t *Tenant) MinIOHealthCheck() bool {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return false
+		return err
 	}
+	resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("MinIO cluster health check returned %s", resp.Status)
+	}
+	return nil
 }
 
-// NewMinIOAdmin initializes a new madmin.Client for operator interaction
-func (t *Tenant) NewMinIOAdmin(minioSecret map[string][]byte) (*madmin.AdminClient, error) {
-	return t.NewMinIOAdminForAddress("", minioSecret)
+// NewMinIOAdmin initializes a new madmin.Client for operator interaction, the MinIO certificate is verified against
+// rootCAs
+func (t *Tenant) NewMinIOAdmin(minioSecret map[string][]byte, rootCAs *x509.CertPool) (*madmin.AdminClient, error) {
+	return t.NewMinIOAdminForAddress("", minioSecret, rootCAs)
 }
 
-// NewMinIOAdminForAddress initializes a new madmin.Client for operator interaction
-func (t *Tenant) NewMinIOAdminForAddress(address string, minioSecret map[string][]byte) (*madmin.AdminClient, error) {
+// NewMinIOAdminForAddress initializes a new madmin.Client for operator interaction, the MinIO certificate is verified
+// against rootCAs
+func (t *Tenant) NewMinIOAdminForAddress(address string, minioSecret map[string][]byte, rootCAs *x509.CertPool) (*madmin.AdminClient, error) {
 	host := address
 	if host == "" {
 		host = t.MinIOServerHostAddress()
@@ -706,8 +869,7 @@ func (t *Tenant) NewMinIOAdminForAddress(address string, minioSecret map[string]
 	}
 
 	if opts.Secure {
-		// FIXME: add trusted CA
-		madmClnt = setUpInsecureTLS(madmClnt)
+		madmClnt = setUpTrustedTLS(madmClnt, rootCAs)
 	}
 
 	return madmClnt, nil
@@ -743,6 +905,100 @@ func (t *Tenant) CreateUsers(madmClnt *madmin.AdminClient, userCredentialSecrets
 	return nil
 }
 
+// ErasureSetDriveCount returns the number of drives of the erasure sets MinIO lays out the drives of the pool in: the
+// largest count from 16 down to 4 dividing the drives of the pool that is symmetric across its servers
+func (z *Pool) ErasureSetDriveCount() int {
+	servers := int(z.Servers)
+	drives := servers * int(z.VolumesPerServer)
+	for count := 16; count >= 4; count-- {
+		if drives%count == 0 && (count%servers == 0 || servers%count == 0) {
+			return count
+		}
+	}
+	return drives
+}
+
+// PoolParity returns the parity of the erasure sets of the pool, from `MINIO_STORAGE_CLASS_STANDARD` if the tenant
+// sets it, else the default parity of MinIO for the size of the erasure sets
+func (t *Tenant) PoolParity(pool *Pool) int {
+	setDriveCount := pool.ErasureSetDriveCount()
+	for _, env := range t.GetEnvVars() {
+		if env.Name != "MINIO_STORAGE_CLASS_STANDARD" {
+			continue
+		}
+		if parity, err := strconv.Atoi(strings.TrimPrefix(env.Value, "EC:")); err == nil && parity >= 0 && parity <= setDriveCount/2 {
+			return parity
+		}
+	}
+	switch {
+	case setDriveCount == 1:
+		return 0
+	case setDriveCount <= 3:
+		return 1
+	case setDriveCount <= 5:
+		return 2
+	case setDriveCount <= 7:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// PoolWriteQuorum returns the number of drives of each erasure set of the pool that must be online to accept writes,
+// the number MinIO reports as `X-Minio-Write-Quorum`
+func (t *Tenant) PoolWriteQuorum(pool *Pool) int {
+	parity := t.PoolParity(pool)
+	dataDrives := pool.ErasureSetDriveCount() - parity
+	if dataDrives == parity {
+		return dataDrives + 1
+	}
+	return dataDrives
+}
+
+// PoolMaxUnavailable returns the number of servers of the pool that can be down without losing write quorum on any
+// erasure set, assuming the servers down hold drives of the same erasure set
+func (t *Tenant) PoolMaxUnavailable(pool *Pool) int32 {
+	setDriveCount := pool.ErasureSetDriveCount()
+	// drives of each erasure set on a single server
+	serverDrives := setDriveCount / int(pool.Servers)
+	if serverDrives < 1 {
+		serverDrives = 1
+	}
+	return int32((setDriveCount - t.PoolWriteQuorum(pool)) / serverDrives)
+}
+
+// ZoneTopologyKey returns the node label identifying the zones the pods of a zone aware pool are spread across
+func (z *Pool) ZoneTopologyKey() string {
+	if z.ZoneAware == nil || z.ZoneAware.TopologyKey == "" {
+		return corev1.LabelTopologyZone
+	}
+	return z.ZoneAware.TopologyKey
+}
+
+// ServersPerZone returns the most servers of a zone aware pool a single zone holds once they are spread evenly
+func (z *Pool) ServersPerZone() int32 {
+	if z.ZoneAware == nil || z.ZoneAware.Zones <= 0 {
+		return z.Servers
+	}
+	return (z.Servers + z.ZoneAware.Zones - 1) / z.ZoneAware.Zones
+}
+
+// validateZoneAwareness verifies the erasure sets of a zone aware pool keep write quorum when the servers of a zone
+// are lost
+func (t *Tenant) validateZoneAwareness(zi int, pool *Pool) error {
+	if pool.ZoneAware == nil {
+		return nil
+	}
+	if pool.ZoneAware.Zones < 2 {
+		return fmt.Errorf("pool #%d must be spread across at least 2 zones to be zone aware", zi)
+	}
+	if perZone, tolerated := pool.ServersPerZone(), t.PoolMaxUnavailable(pool); perZone > tolerated {
+		return fmt.Errorf("pool #%d places up to %d servers per zone but its erasure sets keep write quorum with at most %d servers down with parity %d, add zones or servers, or raise the parity",
+			zi, perZone, tolerated, t.PoolParity(pool))
+	}
+	return nil
+}
+
 // Validate validate single pool as per MinIO deployment requirements
 func (z *Pool) Validate(zi int) error {
 	// Make sure the replicas are not 0 on any pool
@@ -810,20 +1066,205 @@ func (t *Tenant) Validate() error {
 		if err := pool.Validate(zi); err != nil {
 			return err
 		}
+		if err := t.validateZoneAwareness(zi, &pool); err != nil {
+			return err
+		}
+	}
+
+	switch t.Spec.ReclaimPolicy {
+	case "", ReclaimPolicyRetain, ReclaimPolicyDelete, ReclaimPolicySnapshot:
+	default:
+		return fmt.Errorf("reclaimPolicy must be one of %s, %s or %s", ReclaimPolicyRetain, ReclaimPolicyDelete, ReclaimPolicySnapshot)
+	}
+
+	switch t.Spec.UpgradeStrategy {
+	case "", UpgradeStrategyInPlace, UpgradeStrategyRollingUpdate:
+	default:
+		return fmt.Errorf("upgradeStrategy must be one of %s or %s", UpgradeStrategyInPlace, UpgradeStrategyRollingUpdate)
+	}
+
+	if t.Spec.UpgradeHealthWindow != nil && t.Spec.UpgradeHealthWindow.Duration < 0 {
+		return fmt.Errorf("upgradeHealthWindow can't be negative")
+	}
+
+	if t.Spec.AutoReplaceDrives != nil {
+		switch t.Spec.AutoReplaceDrives.Policy {
+		case "", DriveReplacementPolicyReport, DriveReplacementPolicyReplace:
+		default:
+			return fmt.Errorf("autoReplaceDrives.policy must be one of %s or %s", DriveReplacementPolicyReport, DriveReplacementPolicyReplace)
+		}
+		if t.Spec.AutoReplaceDrives.OfflineThreshold != nil && t.Spec.AutoReplaceDrives.OfflineThreshold.Duration < 0 {
+			return fmt.Errorf("autoReplaceDrives.offlineThreshold can't be negative")
+		}
+	}
+
+	if t.Spec.Ingress != nil {
+		if t.Spec.Ingress.MinIOHost == "" && t.Spec.Ingress.ConsoleHost == "" {
+			return errors.New("ingress must route at least one of minioHost or consoleHost")
+		}
+		if t.Spec.Ingress.Gateway != nil && t.Spec.Ingress.Gateway.Name == "" {
+			return errors.New("ingress.gateway.name must be specified")
+		}
+	}
+
+	if t.Spec.NetworkPolicy != nil {
+		for _, cidr := range t.Spec.NetworkPolicy.ClientCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("networkPolicy.clientCIDRs: %s is not a valid CIDR", cidr)
+			}
+		}
+		for _, namespace := range t.Spec.NetworkPolicy.ClientNamespaces {
+			if namespace == "" {
+				return errors.New("networkPolicy.clientNamespaces can't contain an empty namespace")
+			}
+		}
+	}
+
+	if t.Spec.ExposeServices != nil {
+		if err := t.Spec.ExposeServices.MinIOService.Validate("minioService"); err != nil {
+			return err
+		}
+		if err := t.Spec.ExposeServices.ConsoleService.Validate("consoleService"); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// Set up admin client to use self certificates
-func setUpInsecureTLS(api *madmin.AdminClient) *madmin.AdminClient {
+// Validate returns an error if the exposure of a service is invalid
+func (e *ServiceExposure) Validate(name string) error {
+	if e == nil {
+		return nil
+	}
+	switch e.Type {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+	default:
+		return fmt.Errorf("exposeServices.%s.type must be one of %s, %s or %s", name, corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer)
+	}
+	switch e.ExternalTrafficPolicy {
+	case "", corev1.ServiceExternalTrafficPolicyTypeCluster, corev1.ServiceExternalTrafficPolicyTypeLocal:
+	default:
+		return fmt.Errorf("exposeServices.%s.externalTrafficPolicy must be one of %s or %s", name, corev1.ServiceExternalTrafficPolicyTypeCluster, corev1.ServiceExternalTrafficPolicyTypeLocal)
+	}
+	if e.NodePort < 0 || e.NodePort > 65535 {
+		return fmt.Errorf("exposeServices.%s.nodePort must be a valid port number", name)
+	}
+	for _, sourceRange := range e.LoadBalancerSourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			return fmt.Errorf("exposeServices.%s.loadBalancerSourceRanges: %s is not a valid CIDR", name, sourceRange)
+		}
+	}
+	if e.LoadBalancerIP != "" && net.ParseIP(e.LoadBalancerIP) == nil {
+		return fmt.Errorf("exposeServices.%s.loadBalancerIP: %s is not a valid IP", name, e.LoadBalancerIP)
+	}
+	return nil
+}
+
+// MinIOServiceExposure returns how the MinIO service is exposed outside of the cluster, nil if it isn't
+func (t *Tenant) MinIOServiceExposure() *ServiceExposure {
+	if t.Spec.ExposeServices == nil {
+		return nil
+	}
+	if t.Spec.ExposeServices.MinIOService != nil {
+		return t.Spec.ExposeServices.MinIOService
+	}
+	if t.Spec.ExposeServices.MinIO {
+		return &ServiceExposure{Type: corev1.ServiceTypeLoadBalancer}
+	}
+	return nil
+}
+
+// ConsoleServiceExposure returns how the Console service is exposed outside of the cluster, nil if it isn't
+func (t *Tenant) ConsoleServiceExposure() *ServiceExposure {
+	if t.Spec.ExposeServices == nil {
+		return nil
+	}
+	if t.Spec.ExposeServices.ConsoleService != nil {
+		return t.Spec.ExposeServices.ConsoleService
+	}
+	if t.Spec.ExposeServices.Console {
+		return &ServiceExposure{Type: corev1.ServiceTypeLoadBalancer}
+	}
+	return nil
+}
+
+// HasFinalizer returns true if the tenant carries the operator finalizer
+func (t *Tenant) HasFinalizer() bool {
+	for _, f := range t.Finalizers {
+		if f == TenantFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// RestartRequested returns true if a rolling restart was requested through `spec.restartRequestedAt` and it's not
+// completed yet. Any new value requests a restart, the value is set with the clock of the client so it's not compared
+// with the time of the last restart.
+func (t *Tenant) RestartRequested() bool {
+	if t.Spec.RestartRequestedAt == nil {
+		return false
+	}
+	return t.Status.RestartedAt == nil || !t.Status.RestartedAt.Equal(t.Spec.RestartRequestedAt)
+}
+
+// UpgradeHealthWindow returns how long the tenant health is watched after the pools are moved to a new image
+func (t *Tenant) UpgradeHealthWindow() time.Duration {
+	if t.Spec.UpgradeHealthWindow == nil {
+		return DefaultUpgradeHealthWindow
+	}
+	return t.Spec.UpgradeHealthWindow.Duration
+}
+
+// InUpgradeHealthWindow returns true while a failed upgrade of the tenant can still be rolled back automatically
+func (t *Tenant) InUpgradeHealthWindow() bool {
+	if t.Status.UpgradedAt == nil {
+		return false
+	}
+	return time.Since(t.Status.UpgradedAt.Time) < t.UpgradeHealthWindow()
+}
+
+// TargetImage returns the image the pools must run, `spec.image` unless the pools were rolled back from it, in which
+// case they stay on the last known good image until `spec.image` changes
+func (t *Tenant) TargetImage() string {
+	if t.Status.RolledBackImage != "" && t.Spec.Image == t.Status.RolledBackImage && t.Status.LastKnownGoodImage != "" {
+		return t.Status.LastKnownGoodImage
+	}
+	return t.Spec.Image
+}
+
+// Paused returns true if the Operator must only observe the tenant, through `spec.paused` or the paused annotation
+func (t *Tenant) Paused() bool {
+	if t.Spec.Paused {
+		return true
+	}
+	paused, err := strconv.ParseBool(t.Annotations[PausedAnnotation])
+	return err == nil && paused
+}
+
+// AutoReplaceDrives returns true if the drives offline for longer than the threshold are replaced
+func (t *Tenant) AutoReplaceDrives() bool {
+	return t.Spec.AutoReplaceDrives != nil && t.Spec.AutoReplaceDrives.Policy == DriveReplacementPolicyReplace
+}
+
+// DriveOfflineThreshold returns how long a drive must be offline before it's replaced
+func (t *Tenant) DriveOfflineThreshold() time.Duration {
+	if t.Spec.AutoReplaceDrives == nil || t.Spec.AutoReplaceDrives.OfflineThreshold == nil {
+		return DefaultDriveOfflineThreshold
+	}
+	return t.Spec.AutoReplaceDrives.OfflineThreshold.Duration
+}
+
+// Set up admin client to verify the MinIO certificate against rootCAs
+func setUpTrustedTLS(api *madmin.AdminClient, rootCAs *x509.CertPool) *madmin.AdminClient {
 	// Keep TLS config.
 	tlsConfig := &tls.Config{
 		// Can't use SSLv3 because of POODLE and BEAST
 		// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 		// Can't use TLSv1.1 because of RC4 cipher usage
-		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: true,
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    rootCAs,
 	}
 
 	var transport http.RoundTripper = &http.Transport{
@@ -933,6 +1374,18 @@ func GetMonitoringInterval() int {
 	return monitoringInterval
 }
 
+// GetMonitoringWorkers returns how many tenants are checked for cluster/health at the same time
+func GetMonitoringWorkers() int {
+	monitoringWorkersOnce.Do(func() {
+		monitoringWorkers = DefaultMonitoringWorkers
+		val, err := strconv.Atoi(envGet(monitoringWorkersEnv, ""))
+		if err == nil && val > 0 {
+			monitoringWorkers = val
+		}
+	})
+	return monitoringWorkers
+}
+
 // GetTenantServiceURL gets tenant's service url with the proper scheme and port
 func (t *Tenant) GetTenantServiceURL() (svcURL string) {
 	scheme := "http"
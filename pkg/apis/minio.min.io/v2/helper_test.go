@@ -221,3 +221,226 @@ func TestCompareEnvs(t *testing.T) {
 		})
 	}
 }
+
+func TestLegacyNames(t *testing.T) {
+	mt := Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"}}
+
+	t.Run("new tenants get names of their own", func(t *testing.T) {
+		mt.Status.MinIOServiceName = "tenant-a"
+		assert.Equal(t, "tenant-a", mt.MinIOCIServiceName())
+		assert.Equal(t, "tenant-a-operator-webhook-secret", mt.WebhookSecretName())
+		assert.Equal(t, "tenant-a-operator-tls", mt.OperatorTLSSecretName())
+	})
+
+	t.Run("legacy tenants keep their names", func(t *testing.T) {
+		mt.Status.MinIOServiceName = LegacyMinIOCIServiceName
+		assert.Equal(t, "minio", mt.MinIOCIServiceName())
+		assert.Equal(t, "operator-webhook-secret", mt.WebhookSecretName())
+		assert.Equal(t, "operator-tls", mt.OperatorTLSSecretName())
+	})
+}
+
+func TestServerPools(t *testing.T) {
+	mt := Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: TenantSpec{
+			Pools: []Pool{
+				{Name: "ss-0", Servers: 4, VolumesPerServer: 4},
+				{Name: "pool-2", Servers: 4, VolumesPerServer: 2},
+			},
+		},
+		Status: TenantStatus{
+			Pools: []PoolStatus{
+				{SSName: "test-zone-0", State: PoolInitialized},
+				{SSName: "test-pool-1", State: PoolDecommissioning, Name: "pool-1", Servers: 2, VolumesPerServer: 1},
+			},
+		},
+	}
+
+	t.Run("decommissioning pools keep their position", func(t *testing.T) {
+		pools := mt.ServerPools()
+		require.Len(t, pools, 3)
+		assert.Equal(t, "ss-0", pools[0].Name)
+		assert.Equal(t, "pool-1", pools[1].Name)
+		assert.Equal(t, int32(2), pools[1].Servers)
+		assert.Equal(t, "pool-2", pools[2].Name)
+		assert.Equal(t, []string{
+			"test-zone-0-{0...3}.test-hl.default.svc.cluster.local",
+			"test-pool-1-{0...1}.test-hl.default.svc.cluster.local",
+			"test-pool-2-{0...3}.test-hl.default.svc.cluster.local",
+		}, mt.MinIOHosts())
+	})
+
+	t.Run("decommissioned pools are left out", func(t *testing.T) {
+		mt.Status.Pools[1].State = PoolDecommissioned
+		pools := mt.ServerPools()
+		require.Len(t, pools, 2)
+		assert.Equal(t, "ss-0", pools[0].Name)
+		assert.Equal(t, "pool-2", pools[1].Name)
+	})
+
+	t.Run("migrated pools run on a new statefulset", func(t *testing.T) {
+		mt.Status.Pools = []PoolStatus{
+			{SSName: "test-pool-2", State: PoolInitialized, Name: "pool-2", Servers: 2, VolumesPerServer: 2, MigratingTo: "test-pool-2-m1"},
+			{SSName: "test-zone-0", State: PoolInitialized},
+			{SSName: "test-pool-2-m1", State: PoolCreated},
+		}
+		assert.Equal(t, 2, mt.PoolStatusIndex(&mt.Spec.Pools[1]))
+		assert.Equal(t, "test-pool-2-m1", mt.MinIOStatefulSetNameForPool(&mt.Spec.Pools[1]))
+		assert.Equal(t, "test-pool-2-m2", mt.MigratedPoolStatefulsetName(&mt.Spec.Pools[1], "test-pool-2-m1"))
+		assert.Equal(t, []string{"test-pool-2", "test-zone-0", "test-pool-2-m1"}, mt.ServerPoolStatefulSets())
+		assert.Equal(t, []string{
+			"test-pool-2-{0...1}.test-hl.default.svc.cluster.local",
+			"test-zone-0-{0...3}.test-hl.default.svc.cluster.local",
+			"test-pool-2-m1-{0...3}.test-hl.default.svc.cluster.local",
+		}, mt.MinIOHosts())
+	})
+
+	t.Run("pools migrated back to their previous servers run on a new statefulset", func(t *testing.T) {
+		// pool-2 went from 4 to 2 servers and now goes back to 4 servers
+		mt.Status.Pools = []PoolStatus{
+			{SSName: "test-pool-2", State: PoolDecommissioned, Name: "pool-2", Servers: 4, VolumesPerServer: 2, MigratingTo: "test-pool-2-m1"},
+			{SSName: "test-zone-0", State: PoolInitialized},
+			{SSName: "test-pool-2-m1", State: PoolInitialized},
+		}
+		from := mt.MinIOStatefulSetNameForPool(&mt.Spec.Pools[1])
+		assert.Equal(t, "test-pool-2-m1", from)
+		to := mt.MigratedPoolStatefulsetName(&mt.Spec.Pools[1], from)
+		assert.Equal(t, "test-pool-2-m2", to)
+		assert.NotEqual(t, "test-pool-2", to)
+	})
+}
+
+func TestRolledBackImage(t *testing.T) {
+	mt := Tenant{
+		Spec: TenantSpec{Image: "minio/minio:RELEASE.2021-08-05T22-01-19Z"},
+		Status: TenantStatus{
+			LastKnownGoodImage: "minio/minio:RELEASE.2021-07-30T00-02-00Z",
+			RolledBackImage:    "minio/minio:RELEASE.2021-08-05T22-01-19Z",
+		},
+	}
+
+	t.Run("rolled back image is replaced by the last known good image", func(t *testing.T) {
+		mt.EnsureDefaults()
+		assert.Equal(t, "minio/minio:RELEASE.2021-07-30T00-02-00Z", mt.TargetImage())
+		assert.Equal(t, "minio/minio:RELEASE.2021-08-05T22-01-19Z", mt.Spec.Image)
+	})
+
+	t.Run("a new image is kept", func(t *testing.T) {
+		mt.Spec.Image = "minio/minio:RELEASE.2021-08-17T20-53-08Z"
+		mt.EnsureDefaults()
+		assert.Equal(t, "minio/minio:RELEASE.2021-08-17T20-53-08Z", mt.TargetImage())
+	})
+}
+
+func TestPaused(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant Tenant
+		want   bool
+	}{
+		{
+			name: "Not paused",
+		},
+		{
+			name:   "Paused through the spec",
+			tenant: Tenant{Spec: TenantSpec{Paused: true}},
+			want:   true,
+		},
+		{
+			name:   "Paused through the annotation",
+			tenant: Tenant{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{PausedAnnotation: "true"}}},
+			want:   true,
+		},
+		{
+			name:   "Annotation set to false",
+			tenant: Tenant{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{PausedAnnotation: "false"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tenant.Paused())
+		})
+	}
+}
+
+func TestServiceExposure(t *testing.T) {
+	mt := Tenant{Spec: TenantSpec{ExposeServices: &ExposeServices{MinIO: true}}}
+
+	t.Run("exposed services default to load balancers", func(t *testing.T) {
+		require.NotNil(t, mt.MinIOServiceExposure())
+		assert.Equal(t, corev1.ServiceTypeLoadBalancer, mt.MinIOServiceExposure().Type)
+		assert.Nil(t, mt.ConsoleServiceExposure())
+	})
+
+	t.Run("customized exposure takes precedence", func(t *testing.T) {
+		mt.Spec.ExposeServices.MinIOService = &ServiceExposure{Type: corev1.ServiceTypeNodePort, NodePort: 30900}
+		assert.Equal(t, mt.Spec.ExposeServices.MinIOService, mt.MinIOServiceExposure())
+	})
+
+	t.Run("invalid exposure", func(t *testing.T) {
+		assert.Error(t, (&ServiceExposure{Type: corev1.ServiceTypeExternalName}).Validate("minioService"))
+		assert.Error(t, (&ServiceExposure{LoadBalancerSourceRanges: []string{"10.0.0.1"}}).Validate("minioService"))
+		assert.Error(t, (&ServiceExposure{LoadBalancerIP: "metallb"}).Validate("minioService"))
+		assert.NoError(t, (&ServiceExposure{LoadBalancerSourceRanges: []string{"10.0.0.0/8"}, LoadBalancerIP: "192.168.1.240"}).Validate("minioService"))
+	})
+}
+
+func TestIngress(t *testing.T) {
+	mt := Tenant{Spec: TenantSpec{
+		S3:                 &S3Features{BucketDNS: true},
+		Ingress:            &IngressConfig{MinIOHost: "minio.example.com"},
+		ExternalCertSecret: []*LocalCertificateReference{{Name: "opaque", Type: "Opaque"}, {Name: "minio-tls", Type: "kubernetes.io/tls"}},
+	}}
+
+	t.Run("bucket subdomains are routed with bucket DNS", func(t *testing.T) {
+		assert.Equal(t, []string{"minio.example.com", "*.minio.example.com"}, mt.MinIOIngressHosts())
+	})
+
+	t.Run("TLS secret defaults to the external certificate", func(t *testing.T) {
+		assert.Equal(t, "minio-tls", mt.MinIOIngressTLSSecret())
+		mt.Spec.Ingress.MinIOTLSSecret = &corev1.LocalObjectReference{Name: "ingress-tls"}
+		assert.Equal(t, "ingress-tls", mt.MinIOIngressTLSSecret())
+	})
+
+	t.Run("console host requires the console", func(t *testing.T) {
+		mt.Spec.Ingress.ConsoleHost = "console.example.com"
+		assert.Equal(t, "", mt.ConsoleIngressHost())
+	})
+}
+
+func TestPoolMaxUnavailable(t *testing.T) {
+	tests := []struct {
+		servers, volumes int32
+		env              []corev1.EnvVar
+		writeQuorum      int
+		maxUnavailable   int32
+	}{
+		{servers: 4, volumes: 4, writeQuorum: 12, maxUnavailable: 1},
+		{servers: 4, volumes: 1, writeQuorum: 3, maxUnavailable: 1},
+		{servers: 16, volumes: 1, writeQuorum: 12, maxUnavailable: 4},
+		{servers: 2, volumes: 2, writeQuorum: 3, maxUnavailable: 0},
+		{servers: 8, volumes: 4, env: []corev1.EnvVar{{Name: "MINIO_STORAGE_CLASS_STANDARD", Value: "EC:2"}}, writeQuorum: 14, maxUnavailable: 1},
+	}
+	for _, tt := range tests {
+		mt := Tenant{Spec: TenantSpec{Env: tt.env}}
+		pool := &Pool{Servers: tt.servers, VolumesPerServer: tt.volumes}
+		assert.Equal(t, tt.writeQuorum, mt.PoolWriteQuorum(pool), "write quorum of %d servers with %d volumes", tt.servers, tt.volumes)
+		assert.Equal(t, tt.maxUnavailable, mt.PoolMaxUnavailable(pool), "max unavailable of %d servers with %d volumes", tt.servers, tt.volumes)
+	}
+}
+
+func TestZoneAwareness(t *testing.T) {
+	mt := Tenant{}
+	pool := &Pool{Servers: 6, VolumesPerServer: 4, ZoneAware: &ZoneAwareness{Zones: 3}}
+	assert.Equal(t, int32(2), pool.ServersPerZone())
+	assert.Equal(t, corev1.LabelTopologyZone, pool.ZoneTopologyKey())
+	assert.NoError(t, mt.validateZoneAwareness(0, pool))
+
+	// erasure sets of 16 drives, 4 on every server, can only lose one server
+	pool = &Pool{Servers: 4, VolumesPerServer: 4, ZoneAware: &ZoneAwareness{Zones: 2}}
+	assert.Error(t, mt.validateZoneAwareness(0, pool))
+
+	pool.ZoneAware.Zones = 1
+	assert.Error(t, mt.validateZoneAwareness(0, pool))
+}
@@ -52,9 +52,13 @@ const InitContainerImage = "busybox:1.32"
 
 // MinIO Related Names
 
-// MinIOStatefulSetNameForPool returns the name for MinIO StatefulSet
+// MinIOStatefulSetNameForPool returns the name for MinIO StatefulSet, the name recorded in the status is used once the
+// pool is deployed
 func (t *Tenant) MinIOStatefulSetNameForPool(z *Pool) string {
-	return fmt.Sprintf("%s-%s", t.Name, z.Name)
+	if pi := t.PoolStatusIndex(z); pi >= 0 {
+		return t.Status.Pools[pi].SSName
+	}
+	return t.PoolStatefulsetName(z)
 }
 
 // MinIOWildCardName returns the wild card name for all MinIO Pods in current StatefulSet
@@ -79,12 +83,18 @@ func (t *Tenant) MinIOHLServiceName() string {
 	return t.Name + MinIOHLSvcNameSuffix
 }
 
+// LegacyMinIOCIServiceName is the name of the Cluster IP service of tenants deployed when only one Tenant was allowed
+// per namespace
+const LegacyMinIOCIServiceName = "minio"
+
 // MinIOCIServiceName returns the name of Cluster IP service that is created to communicate
-// with current MinIO StatefulSet pods
+// with current MinIO StatefulSet pods. The name recorded in the status takes precedence so tenants deployed
+// with the legacy `minio` service keep using it.
 func (t *Tenant) MinIOCIServiceName() string {
-	// DO NOT CHANGE, this should be constant
-	// This is possible because each namespace has only one Tenant
-	return "minio"
+	if t.Status.MinIOServiceName != "" {
+		return t.Status.MinIOServiceName
+	}
+	return t.Name
 }
 
 // MinIOBucketBaseDomain returns the base domain name for buckets
@@ -130,6 +140,33 @@ func (t *Tenant) MinIOClientCSRName() string {
 	return t.Name + "-client-" + t.Namespace + CSRNameSuffix
 }
 
+// LegacyOperatorTLSSecretName is the name of the Secret the Operator public certificate was copied to in the namespace
+// of tenants deployed when only one Tenant was allowed per namespace
+const LegacyOperatorTLSSecretName = "operator-tls"
+
+// hasLegacyNames returns true for tenants deployed when only one Tenant was allowed per namespace, they keep the names
+// of their services and secrets
+func (t *Tenant) hasLegacyNames() bool {
+	return t.Status.MinIOServiceName == LegacyMinIOCIServiceName
+}
+
+// WebhookSecretName returns the name of the Secret holding the credentials MinIO uses to call the Operator webhooks
+func (t *Tenant) WebhookSecretName() string {
+	if t.hasLegacyNames() {
+		return WebhookSecret
+	}
+	return t.Name + "-" + WebhookSecret
+}
+
+// OperatorTLSSecretName returns the name of the Secret the Operator public certificate is copied to in the tenant
+// namespace
+func (t *Tenant) OperatorTLSSecretName() string {
+	if t.hasLegacyNames() {
+		return LegacyOperatorTLSSecretName
+	}
+	return t.Name + "-operator" + TLSSecretSuffix
+}
+
 // KES Related Names
 
 // KESJobName returns the name for KES Key Job
@@ -188,6 +225,14 @@ func (t *Tenant) PoolStatefulsetName(pool *Pool) string {
 	return fmt.Sprintf("%s-%s", t.Name, pool.Name)
 }
 
+// MigratedPoolStatefulsetName returns the name of the statefulset a pool is migrated to when its number of servers
+// changes. The name carries a migration count that grows with every migration of the pool, so a pool never goes back
+// to the name of a statefulset it ran on before, whose PVCs may have been retained.
+func (t *Tenant) MigratedPoolStatefulsetName(pool *Pool, from string) string {
+	migration, _ := t.poolMigration(pool, from)
+	return fmt.Sprintf("%s-m%d", t.PoolStatefulsetName(pool), migration+1)
+}
+
 // LegacyStatefulsetName returns the name of a statefulset for a given pool
 func (t *Tenant) LegacyStatefulsetName(pool *Pool) string {
 	zoneName := strings.Replace(pool.Name, StatefulSetPrefix, StatefulSetLegacyPrefix, 1)
@@ -278,3 +323,18 @@ func (t *Tenant) PrometheusServiceName() string {
 func (t *Tenant) PrometheusHLServiceName() string {
 	return t.Name + PrometheusHLSvcNameSuffix
 }
+
+// IngressName returns the name of the Ingress routing to the MinIO and Console services
+func (t *Tenant) IngressName() string {
+	return t.Name + IngressNameSuffix
+}
+
+// MinIOHTTPRouteName returns the name of the HTTPRoute routing to the MinIO service
+func (t *Tenant) MinIOHTTPRouteName() string {
+	return t.MinIOCIServiceName() + HTTPRouteNameSuffix
+}
+
+// ConsoleHTTPRouteName returns the name of the HTTPRoute routing to the Console service
+func (t *Tenant) ConsoleHTTPRouteName() string {
+	return t.ConsoleCIServiceName() + HTTPRouteNameSuffix
+}
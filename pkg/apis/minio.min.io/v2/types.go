@@ -96,6 +96,16 @@ type TenantSpec struct {
 	ImagePullSecret corev1.LocalObjectReference `json:"imagePullSecret,omitempty"`
 	// *Optional* +
 	//
+	// Registry mirror the Operator pulls `image` from to collect the MinIO binaries for an `InPlace` upgrade, for example an in-cluster registry such as `registry.registry-ns.svc.cluster.local:5000`. The repository and tag of `image` are kept. Images found in the artifacts directory mounted into the Operator take precedence over the mirror. +
+	// +optional
+	ImageMirror string `json:"imageMirror,omitempty"`
+	// *Optional* +
+	//
+	// Allow the Operator to reach `imageMirror` over plain HTTP or without verifying its TLS certificate, for in-cluster registries that don't serve trusted TLS. Defaults to `false`. +
+	// +optional
+	ImageMirrorInsecure bool `json:"imageMirrorInsecure,omitempty"`
+	// *Optional* +
+	//
 	// Pod Management Policy for pod created by StatefulSet
 	// +optional
 	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
@@ -271,6 +281,16 @@ type TenantSpec struct {
 	ServiceMetadata *ServiceMetadata `json:"serviceMetadata,omitempty"`
 	// *Optional* +
 	//
+	// Directs the Operator to route traffic from outside of the cluster to the MinIO and Console services, through an Ingress or through Gateway API HTTPRoutes. +
+	// +optional
+	Ingress *IngressConfig `json:"ingress,omitempty"`
+	// *Optional* +
+	//
+	// Directs the Operator to create https://kubernetes.io/docs/concepts/services-networking/network-policies/[NetworkPolicies] restricting the traffic allowed into the MinIO, KES and Log database pods of the tenant. +
+	// +optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+	// *Optional* +
+	//
 	// An array of https://kubernetes.io/docs/concepts/configuration/secret/[Kubernetes opaque secrets] to use for generating MinIO users during tenant provisioning. +
 	//
 	// Each element in the array is an object consisting of a key-value pair `name: <string>`, where the `<string>` references an opaque Kubernetes secret. +
@@ -289,8 +309,103 @@ type TenantSpec struct {
 	// Enable JSON, Anonymous logging for MinIO tenants.
 	// +optional
 	Logging *Logging `json:"logging,omitempty"`
+	// *Optional* +
+	//
+	// What the Operator does with the PersistentVolumeClaims of each pool when the Tenant is deleted or a decommissioned pool is removed. Specify one of the following: +
+	//
+	// * `Retain` - Leave the PVCs in place (Default) +
+	//
+	// * `Delete` - Delete the PVCs +
+	//
+	// * `Snapshot` - Take a `VolumeSnapshot` of every PVC, wait for all snapshots to be ready to use, then delete the PVCs +
+	// +optional
+	ReclaimPolicy ReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	// *Optional* +
+	//
+	// The name of the `VolumeSnapshotClass` used when `reclaimPolicy` is `Snapshot`. If not specified, the cluster default snapshot class is used. +
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+	// *Optional* +
+	//
+	// Set to the current time to restart the MinIO pods of the tenant one at a time. The Operator restarts the pods running when it picks up a new value, only deleting the next pod once all pods are ready and MinIO reports the cluster keeps write quorum without it. +
+	// +optional
+	RestartRequestedAt *metav1.Time `json:"restartRequestedAt,omitempty"`
+	// *Optional* +
+	//
+	// How the Operator moves the tenant to a new `image`. Specify one of the following: +
+	//
+	// * `InPlace` - Download the new MinIO binary and update all servers at once through the MinIO admin API (Default) +
+	//
+	// * `RollingUpdate` - Roll the pool StatefulSets to the new image one pool at a time, waiting for each pool to be ready and the tenant to be healthy before moving to the next pool +
+	// +optional
+	UpgradeStrategy UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+	// *Optional* +
+	//
+	// How long the Operator watches the tenant health after moving the pools to a new `image`. If MinIO reports the tenant lost write quorum, or is unreachable, for 3 consecutive health checks within this window, the pool StatefulSets are rolled back to `status.lastKnownGoodImage`. Defaults to `5m`, set to `0s` to disable automatic rollbacks. +
+	// +optional
+	UpgradeHealthWindow *metav1.Duration `json:"upgradeHealthWindow,omitempty"`
+	// *Optional* +
+	//
+	// Configures how the Operator handles drives whose volume or node is gone, or that MinIO reports offline. Offline drives are always reported in the tenant status and through events, the `policy` decides if they are also replaced. +
+	// +optional
+	AutoReplaceDrives *AutoReplaceDrives `json:"autoReplaceDrives,omitempty"`
+	// *Optional* +
+	//
+	// Set to `true` to stop the Operator from changing anything on behalf of the tenant. The Operator keeps monitoring the tenant health and reports the changes it would have made as events. The `min.io/paused: "true"` annotation has the same effect. +
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// AutoReplaceDrives (`autoReplaceDrives`) defines how the Operator replaces the drives of a tenant found offline.
+type AutoReplaceDrives struct {
+	// *Optional* +
+	//
+	// Specify one of the following: +
+	//
+	// * `Report` - Only report offline drives (Default) +
+	//
+	// * `Replace` - Delete the pod and the PVC of a drive whose volume or node is gone for longer than `offlineThreshold`, so the StatefulSet recreates them on healthy storage. Drives MinIO reports offline while their volume is still there are only reported. The drives of one pod are replaced at a time, and only once MinIO finished healing the previously replaced drives +
+	// +optional
+	Policy DriveReplacementPolicy `json:"policy,omitempty"`
+	// *Optional* +
+	//
+	// How long the volume or the node of a drive must be gone before the drive is replaced. Defaults to `10m`. +
+	// +optional
+	OfflineThreshold *metav1.Duration `json:"offlineThreshold,omitempty"`
 }
 
+// DriveReplacementPolicy describes what the Operator does with the drives found offline
+type DriveReplacementPolicy string
+
+const (
+	// DriveReplacementPolicyReport only reports the drives found offline
+	DriveReplacementPolicyReport DriveReplacementPolicy = "Report"
+	// DriveReplacementPolicyReplace replaces the drives offline for longer than the threshold
+	DriveReplacementPolicyReplace DriveReplacementPolicy = "Replace"
+)
+
+// UpgradeStrategy describes how the tenant is moved to a new MinIO image
+type UpgradeStrategy string
+
+const (
+	// UpgradeStrategyInPlace swaps the MinIO binary inside the running containers
+	UpgradeStrategyInPlace UpgradeStrategy = "InPlace"
+	// UpgradeStrategyRollingUpdate rolls the pool StatefulSets to the new image one pool at a time
+	UpgradeStrategyRollingUpdate UpgradeStrategy = "RollingUpdate"
+)
+
+// ReclaimPolicy describes what happens to the pool volumes when a Tenant is deleted or a pool is decommissioned
+type ReclaimPolicy string
+
+const (
+	// ReclaimPolicyRetain leaves the PVCs behind when the tenant is deleted
+	ReclaimPolicyRetain ReclaimPolicy = "Retain"
+	// ReclaimPolicyDelete deletes the PVCs along with the tenant
+	ReclaimPolicyDelete ReclaimPolicy = "Delete"
+	// ReclaimPolicySnapshot takes a VolumeSnapshot of every PVC before deleting them
+	ReclaimPolicySnapshot ReclaimPolicy = "Snapshot"
+)
+
 // Logging describes Logging for MinIO tenants.
 type Logging struct {
 	JSON      bool `json:"json,omitempty"`
@@ -346,6 +461,124 @@ type ExposeServices struct {
 	// Directs the Operator to expose the MinIO Console service. Defaults to `true`. +
 	// +optional
 	Console bool `json:"console,omitempty"`
+	// *Optional* +
+	//
+	// Customizes the Kubernetes service exposing the MinIO object storage. When set, the MinIO service is exposed as specified regardless of `minio`. +
+	// +optional
+	MinIOService *ServiceExposure `json:"minioService,omitempty"`
+	// *Optional* +
+	//
+	// Customizes the Kubernetes service exposing the MinIO Console. When set, the Console service is exposed as specified regardless of `console`. +
+	// +optional
+	ConsoleService *ServiceExposure `json:"consoleService,omitempty"`
+}
+
+// IngressConfig (`ingress`) defines the routes the Operator creates to the MinIO and Console services. The Operator creates an Ingress, or HTTPRoutes attached to a Gateway when `gateway` is set. +
+type IngressConfig struct {
+	// *Optional* +
+	//
+	// The hostname routed to the MinIO S3 API. When bucket DNS is enabled (`s3.bucketDNS`), the bucket subdomains `*.<minioHost>` are routed as well. +
+	// +optional
+	MinIOHost string `json:"minioHost,omitempty"`
+	// *Optional* +
+	//
+	// The hostname routed to the MinIO Console. +
+	// +optional
+	ConsoleHost string `json:"consoleHost,omitempty"`
+	// *Optional* +
+	//
+	// The https://kubernetes.io/docs/concepts/services-networking/ingress/#ingress-class[IngressClass] of the Ingress. +
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// *Optional* +
+	//
+	// Labels to add to the Ingress or the HTTPRoutes. +
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// *Optional* +
+	//
+	// Annotations to add to the Ingress or the HTTPRoutes, for example to configure the ingress controller. +
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// *Optional* +
+	//
+	// The `kubernetes.io/tls` secret the Ingress terminates TLS for `minioHost` with. Defaults to the first `kubernetes.io/tls` secret of `externalCertSecret`. +
+	// +optional
+	MinIOTLSSecret *corev1.LocalObjectReference `json:"minioTLSSecret,omitempty"`
+	// *Optional* +
+	//
+	// The `kubernetes.io/tls` secret the Ingress terminates TLS for `consoleHost` with. Defaults to `console.externalCertSecret` if it is a `kubernetes.io/tls` secret. +
+	// +optional
+	ConsoleTLSSecret *corev1.LocalObjectReference `json:"consoleTLSSecret,omitempty"`
+	// *Optional* +
+	//
+	// The https://gateway-api.sigs.k8s.io/[Gateway API] Gateway to attach HTTPRoutes to instead of creating an Ingress. TLS is terminated by the listeners of the Gateway. +
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// GatewayReference (`gateway`) references the Gateway the HTTPRoutes of the tenant are attached to. +
+type GatewayReference struct {
+	// The name of the Gateway. +
+	Name string `json:"name"`
+	// *Optional* +
+	//
+	// The namespace of the Gateway. Defaults to the namespace of the tenant. +
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// *Optional* +
+	//
+	// The name of the Gateway listener the HTTPRoutes are attached to. Defaults to all the listeners of the Gateway. +
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// NetworkPolicyConfig (`networkPolicy`) defines the clients allowed to reach the MinIO S3 API of the tenant once its pods are isolated by NetworkPolicies. The MinIO pods of the tenant, its Console and Prometheus, and the Operator are always allowed. +
+type NetworkPolicyConfig struct {
+	// *Optional* +
+	//
+	// The namespaces whose pods are allowed to reach the MinIO S3 API, matched by their `kubernetes.io/metadata.name` label. The pods of the tenant namespace are not allowed unless listed. +
+	// +optional
+	ClientNamespaces []string `json:"clientNamespaces,omitempty"`
+	// *Optional* +
+	//
+	// The IP ranges, in CIDR notation, allowed to reach the MinIO S3 API. +
+	// +optional
+	ClientCIDRs []string `json:"clientCIDRs,omitempty"`
+}
+
+// ServiceExposure (`minioService`, `consoleService`) defines how a tenant service is exposed outside of the Kubernetes cluster. +
+type ServiceExposure struct {
+	// *Optional* +
+	//
+	// The type of the service, one of `ClusterIP`, `NodePort` or `LoadBalancer`. Defaults to `LoadBalancer`. +
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// *Optional* +
+	//
+	// The node port of the service for `NodePort` and `LoadBalancer` services. Kubernetes allocates a free node port if not specified. +
+	// +optional
+	NodePort int32 `json:"nodePort,omitempty"`
+	// *Optional* +
+	//
+	// The client IP ranges allowed to reach a `LoadBalancer` service, for load balancer implementations that support it. +
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// *Optional* +
+	//
+	// Routes external traffic to node-local (`Local`) or cluster-wide (`Cluster`) endpoints of `NodePort` and `LoadBalancer` services. `Local` preserves the client source IP. Defaults to `Cluster`. +
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	// *Optional* +
+	//
+	// The class of the load balancer implementation of a `LoadBalancer` service, requires Kubernetes 1.21 or later. The class can't be changed once the service is created. +
+	// +optional
+	LoadBalancerClass string `json:"loadBalancerClass,omitempty"`
+	// *Optional* +
+	//
+	// The static IP requested for a `LoadBalancer` service, for load balancer implementations that support it. +
+	// +optional
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
 }
 
 // CertificateStatus keeps track of all the certificates managed by the operator
@@ -365,12 +598,128 @@ const (
 	PoolCreated PoolState = "PoolCreated"
 	// PoolInitialized indicates if a pool has been observed to be online
 	PoolInitialized PoolState = "PoolInitialized"
+	// PoolDecommissioning indicates a pool removed from the tenant spec is moving its data to the remaining pools
+	PoolDecommissioning PoolState = "PoolDecommissioning"
+	// PoolDecommissioned indicates a pool finished moving its data and its resources are being removed
+	PoolDecommissioned PoolState = "PoolDecommissioned"
 )
 
 // PoolStatus keeps track of all the pools and their current state
 type PoolStatus struct {
 	SSName string    `json:"ssName"`
 	State  PoolState `json:"state"`
+	// *Optional* +
+	//
+	// Name of a pool removed from the spec, recorded along with its size so MinIO keeps serving the pool until it's
+	// decommissioned
+	Name string `json:"name,omitempty"`
+	// *Optional* +
+	//
+	// Number of servers of a pool removed from the spec
+	Servers int32 `json:"servers,omitempty"`
+	// *Optional* +
+	//
+	// Number of volumes per server of a pool removed from the spec
+	VolumesPerServer int32 `json:"volumesPerServer,omitempty"`
+	// *Optional* +
+	//
+	// Storage size the pool volumes are being expanded to
+	ExpandingTo string `json:"expandingTo,omitempty"`
+	// *Optional* +
+	//
+	// Number of pool volumes that already reached the `expandingTo` size
+	ExpandedVolumes int32 `json:"expandedVolumes,omitempty"`
+	// *Optional* +
+	//
+	// Number of pool volumes being expanded
+	TotalVolumes int32 `json:"totalVolumes,omitempty"`
+	// *Optional* +
+	//
+	// Name of the StatefulSet the pool is migrated to after its number of servers changed. The pool is decommissioned
+	// once the new StatefulSet is initialized
+	MigratingTo string `json:"migratingTo,omitempty"`
+	// *Optional* +
+	//
+	// Number of drives of the pool online
+	DrivesOnline int32 `json:"drivesOnline,omitempty"`
+	// *Optional* +
+	//
+	// Number of drives of the pool offline
+	DrivesOffline int32 `json:"drivesOffline,omitempty"`
+	// *Optional* +
+	//
+	// Number of drives of the pool healing
+	DrivesHealing int32 `json:"drivesHealing,omitempty"`
+	// *Optional* +
+	//
+	// Capacity of the online drives of the pool, in bytes
+	RawCapacity int64 `json:"rawCapacity,omitempty"`
+	// *Optional* +
+	//
+	// Capacity of the online drives of the pool left for object data once the erasure code parity is taken out, in bytes
+	UsableCapacity int64 `json:"usableCapacity,omitempty"`
+	// *Optional* +
+	//
+	// Space used on the online drives of the pool, erasure code parity included, in bytes
+	UsedBytes int64 `json:"usedBytes,omitempty"`
+	// *Optional* +
+	//
+	// Number of erasure sets of the pool
+	ErasureSets int32 `json:"erasureSets,omitempty"`
+	// *Optional* +
+	//
+	// Number of erasure sets of the pool with drives offline or healing, that keep their write quorum
+	ErasureSetsDegraded int32 `json:"erasureSetsDegraded,omitempty"`
+	// *Optional* +
+	//
+	// Number of erasure sets of the pool that lost their write quorum
+	ErasureSetsQuorumLost int32 `json:"erasureSetsQuorumLost,omitempty"`
+	// *Optional* +
+	//
+	// Health State of the pool, `red` if an erasure set lost its write quorum, `yellow` if a drive is offline or healing
+	HealthStatus HealthStatus `json:"healthStatus,omitempty"`
+}
+
+// DriveState is the state of a drive found offline
+type DriveState string
+
+const (
+	// DriveOffline MinIO reports the drive offline while its pod is running, the drive is only reported
+	DriveOffline DriveState = "Offline"
+	// DriveLost the volume or the node of the drive is gone, the drive is waiting to be replaced
+	DriveLost DriveState = "Lost"
+	// DriveReplaced the PVC and pod of the drive were deleted, waiting for MinIO to heal the new drive
+	DriveReplaced DriveState = "Replaced"
+)
+
+// DriveStatus keeps track of a drive found offline
+type DriveStatus struct {
+	// Name of the PVC backing the drive
+	PVC string `json:"pvc"`
+	// Name of the pod using the drive
+	Pod   string     `json:"pod"`
+	State DriveState `json:"state"`
+	// *Optional* +
+	//
+	// Why the drive is considered offline
+	Reason string `json:"reason,omitempty"`
+	// Time the drive was first found offline
+	OfflineSince metav1.Time `json:"offlineSince"`
+	// *Optional* +
+	//
+	// Time the PVC and pod of the drive were deleted
+	// +nullable
+	ReplacedAt *metav1.Time `json:"replacedAt,omitempty"`
+}
+
+// RestartStatus keeps track of a rolling restart of the MinIO pods
+type RestartStatus struct {
+	// The `restartRequestedAt` value of the restart
+	RequestedAt metav1.Time `json:"requestedAt"`
+	// *Optional* +
+	//
+	// UIDs of the MinIO pods running when the restart was picked up, the restart completes once none of them is left
+	PendingPods []string `json:"pendingPods,omitempty"`
 }
 
 // HealthStatus represents whether the tenant is healthy, with decreased service or offline
@@ -385,6 +734,84 @@ const (
 	HealthStatusRed HealthStatus = "red"
 )
 
+// Condition types reported in the Tenant status
+const (
+	// TenantConditionReady indicates the tenant has been fully reconciled
+	TenantConditionReady = "Ready"
+	// TenantConditionPoolsProvisioned indicates all the pools have been created and observed online
+	TenantConditionPoolsProvisioned = "PoolsProvisioned"
+	// TenantConditionCertificatesReady indicates the TLS certificates for MinIO are in place
+	TenantConditionCertificatesReady = "CertificatesReady"
+	// TenantConditionKESReady indicates KES has been deployed, only reported when KES is enabled
+	TenantConditionKESReady = "KESReady"
+	// TenantConditionConsoleReady indicates Console has been deployed, only reported when Console is enabled
+	TenantConditionConsoleReady = "ConsoleReady"
+	// TenantConditionUpgrading indicates a MinIO version update is in progress
+	TenantConditionUpgrading = "Upgrading"
+	// TenantConditionDegraded indicates the tenant is misconfigured or has lost resilience
+	TenantConditionDegraded = "Degraded"
+	// TenantConditionPaused indicates the Operator only observes the tenant, without changing it
+	TenantConditionPaused = "Paused"
+)
+
+// Reasons used by the Tenant status conditions
+const (
+	// ReasonInitialized the tenant was fully reconciled
+	ReasonInitialized = "Initialized"
+	// ReasonReconciling the tenant is still being reconciled, the message carries the current state
+	ReasonReconciling = "Reconciling"
+	// ReasonProvisioningPool a pool statefulset is being created
+	ReasonProvisioningPool = "ProvisioningPool"
+	// ReasonWaitingForPools some pools have not been observed online yet
+	ReasonWaitingForPools = "WaitingForPools"
+	// ReasonPoolsInitialized all the pools are online
+	ReasonPoolsInitialized = "PoolsInitialized"
+	// ReasonCertificatesIssued the certificates are in place
+	ReasonCertificatesIssued = "CertificatesIssued"
+	// ReasonCertificatesNotReady the certificates are still being issued or could not be read
+	ReasonCertificatesNotReady = "CertificatesNotReady"
+	// ReasonDeployed the component has been deployed
+	ReasonDeployed = "Deployed"
+	// ReasonNotReady the component could not be deployed yet
+	ReasonNotReady = "NotReady"
+	// ReasonUpgradeInProgress a MinIO version update is in progress
+	ReasonUpgradeInProgress = "UpgradeInProgress"
+	// ReasonUpgradeCompleted the MinIO version update finished
+	ReasonUpgradeCompleted = "UpgradeCompleted"
+	// ReasonUpgradeFailed the MinIO version update failed
+	ReasonUpgradeFailed = "UpgradeFailed"
+	// ReasonUpgradeNotNeeded MinIO is already running the requested version
+	ReasonUpgradeNotNeeded = "UpgradeNotNeeded"
+	// ReasonUpgradeRolledBack the tenant went red after a MinIO version update and was rolled back
+	ReasonUpgradeRolledBack = "UpgradeRolledBack"
+	// ReasonValidationFailed the tenant specification is not valid
+	ReasonValidationFailed = "ValidationFailed"
+	// ReasonInconsistentVersions the pools are running different MinIO versions
+	ReasonInconsistentVersions = "InconsistentVersions"
+	// ReasonNotOwned a statefulset for the tenant is not controlled by the operator
+	ReasonNotOwned = "NotOwned"
+	// ReasonResolved the configuration problem previously reported was resolved
+	ReasonResolved = "Resolved"
+	// ReasonHealthy the tenant health check reports green
+	ReasonHealthy = "Healthy"
+	// ReasonReducedResilience the tenant health check reports yellow
+	ReasonReducedResilience = "ReducedResilience"
+	// ReasonQuorumLost the tenant health check reports red
+	ReasonQuorumLost = "QuorumLost"
+	// ReasonUntrustedCertificate the tenant health check failed because the MinIO certificate couldn't be verified
+	ReasonUntrustedCertificate = "UntrustedCertificate"
+	// ReasonDecommissioningPool indicates a pool removed from the spec is being decommissioned
+	ReasonDecommissioningPool = "DecommissioningPool"
+	// ReasonMigratingPool indicates a pool is being migrated to a new StatefulSet after its number of servers changed
+	ReasonMigratingPool = "MigratingPool"
+	// ReasonPaused the tenant reconciliation was paused through `spec.paused` or the paused annotation
+	ReasonPaused = "Paused"
+	// ReasonVolumeShrinkNotSupported the volume claim template of a pool requests less storage than its volumes have
+	ReasonVolumeShrinkNotSupported = "VolumeShrinkNotSupported"
+	// ReasonVolumeExpansionNotSupported the storage class of a pool doesn't allow growing its volumes
+	ReasonVolumeExpansionNotSupported = "VolumeExpansionNotSupported"
+)
+
 // TenantStatus is the status for a Tenant resource
 type TenantStatus struct {
 	CurrentState      string `json:"currentState"`
@@ -417,6 +844,71 @@ type TenantStatus struct {
 	//
 	// Health State of the tenant
 	HealthStatus HealthStatus `json:"healthStatus,omitempty"`
+	// *Optional* +
+	//
+	// Time of the last health check of the tenant by the Operator
+	// +nullable
+	HealthCheckedAt *metav1.Time `json:"healthCheckedAt,omitempty"`
+	// *Optional* +
+	//
+	// Error of the last health check, empty if the check succeeded
+	HealthCheckError string `json:"healthCheckError,omitempty"`
+	// *Optional* +
+	//
+	// Number of consecutive failed health checks, the Operator checks the tenant less often while they keep failing
+	HealthCheckFailures int32 `json:"healthCheckFailures,omitempty"`
+	// *Optional* +
+	//
+	// Total size of the objects stored in the tenant, as last computed by the MinIO data scanner
+	ObjectsSize int64 `json:"objectsSize,omitempty"`
+	// *Optional* +
+	//
+	// Name of the MinIO Cluster IP service of the tenant
+	MinIOServiceName string `json:"minioServiceName,omitempty"`
+	// *Optional* +
+	//
+	// Drives found offline, and replaced drives MinIO didn't finish healing
+	OfflineDrives []DriveStatus `json:"offlineDrives,omitempty"`
+	// *Optional* +
+	//
+	// The `restartRequestedAt` value of the last rolling restart that completed
+	// +nullable
+	RestartedAt *metav1.Time `json:"restartedAt,omitempty"`
+	// *Optional* +
+	//
+	// The rolling restart in progress
+	// +nullable
+	Restart *RestartStatus `json:"restart,omitempty"`
+	// *Optional* +
+	//
+	// Image all the pools were running while the tenant was healthy, the pools are rolled back to it if an upgrade fails
+	LastKnownGoodImage string `json:"lastKnownGoodImage,omitempty"`
+	// *Optional* +
+	//
+	// Image the pools were rolled back from, the tenant is not upgraded to it again until `spec.image` changes
+	RolledBackImage string `json:"rolledBackImage,omitempty"`
+	// *Optional* +
+	//
+	// Time the pools were last moved to a new image, the upgrade health window starts at this time
+	// +nullable
+	UpgradedAt *metav1.Time `json:"upgradedAt,omitempty"`
+	// *Optional* +
+	//
+	// Number of consecutive failed health checks since the pools were last moved to a new image
+	UpgradeHealthFailures int32 `json:"upgradeHealthFailures,omitempty"`
+	// *Optional* +
+	//
+	// Time of the last failed health check since the pools were last moved to a new image
+	// +nullable
+	UpgradeHealthFailedAt *metav1.Time `json:"upgradeHealthFailedAt,omitempty"`
+	// *Optional* +
+	//
+	// Conditions describing the current state of the tenant: `Ready`, `PoolsProvisioned`, `CertificatesReady`,
+	// `KESReady`, `ConsoleReady`, `Upgrading` and `Degraded`
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // CertificateConfig (`certConfig`) defines controlling attributes associated to any TLS certificate automatically generated by the Operator as part of tenant creation. These fields have no effect if `spec.autoCert: false`.
@@ -485,6 +977,41 @@ type Pool struct {
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 	// *Optional* +
 	//
+	// Specify one or more https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/[topology spread constraints] to apply to pods deployed in the MinIO pool. +
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// *Optional* +
+	//
+	// Directs the Operator to spread the pods of the pool evenly across zones, so every erasure set of the pool keeps write quorum when a zone is lost. The Operator rejects pools whose erasure sets can't lose the servers of a zone. +
+	// +optional
+	ZoneAware *ZoneAwareness `json:"zoneAware,omitempty"`
+	// *Optional* +
+	//
+	// The https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/[liveness probe] of the MinIO container. Defaults to a check of `/minio/health/live` every 20 seconds. +
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+	// *Optional* +
+	//
+	// The readiness probe of the MinIO container. Defaults to a check of `/minio/health/ready` every 20 seconds. +
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+	// *Optional* +
+	//
+	// The startup probe of the MinIO container, holding the liveness and readiness probes until MinIO starts. Defaults to a check of `/minio/health/live` every 10 seconds for up to 5 minutes. Raise its `failureThreshold` for servers whose drives take longer to mount and format. +
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+	// *Optional* +
+	//
+	// The https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/[lifecycle hooks] of the MinIO container, for example a `preStop` hook. +
+	// +optional
+	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
+	// *Optional* +
+	//
+	// A list of https://kubernetes.io/docs/concepts/workloads/pods/init-containers/[init containers] to run before the MinIO container of every pod of the pool. +
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// *Optional* +
+	//
 	// Specify the https://kubernetes.io/docs/tasks/configure-pod-container/security-context/[Security Context] of pods in the pool. The Operator supports only the following pod security fields: +
 	//
 	// * `fsGroup` +
@@ -503,6 +1030,19 @@ type Pool struct {
 	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
 }
 
+// ZoneAwareness (`zoneAware`) defines the zones the pods of a pool are spread across. +
+type ZoneAwareness struct {
+	// *Required* +
+	//
+	// The number of zones the pods of the pool are spread across. The minimum value is `2`. +
+	Zones int32 `json:"zones"`
+	// *Optional* +
+	//
+	// The node label identifying the zone of a node. Defaults to `topology.kubernetes.io/zone`. +
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+}
+
 // ConsoleConfiguration (`console`) defines configuration of the https://github.com/minio/console[MinIO Console] deployed as part of the MinIO Tenant. The Operator automatically configures the Console for connectivity to MinIO server pods in the tenant. +
 //
 // For more complete documentation on this object, see the https://docs.min.io/minio/k8s/reference/minio-operator-reference.html#minio-operator-yaml-reference[MinIO Kubernetes Documentation].
@@ -0,0 +1,379 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// updateCachePath keeps the MinIO binaries extracted from each image, one directory per image digest
+	updateCachePath = "/tmp/webhook/cache/"
+	// updateCacheSize is how many extracted images are kept in the cache
+	updateCacheSize = 3
+	// ociRefNameAnnotation is the annotation carrying the image name in an OCI image layout
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// minioArtifacts are the files needed by the MinIO updater, as found in the MinIO image
+var minioArtifacts = []string{"usr/bin/minio", "usr/bin/minio.sha256sum", "usr/bin/minio.minisig"}
+
+// fetchArtifacts finds the tenant image, extracts the files needed by the MinIO updater (minio, minio.sha256sum &
+// minio.minisig) and keeps them at a pre-defined location (/tmp/webhook/v1/update). The extracted files are cached by
+// image digest so tenants upgrading to the same image don't extract them again.
+func (c *Controller) fetchArtifacts(tenant *miniov2.Tenant) (latest time.Time, err error) {
+	basePath := updatePath
+
+	if err = os.MkdirAll(basePath, 1777); err != nil {
+		return latest, err
+	}
+
+	img, err := c.upgradeImage(tenant)
+	if err != nil {
+		return latest, err
+	}
+
+	cachePath, err := cacheArtifacts(img)
+	if err != nil {
+		return latest, err
+	}
+
+	tag, err := c.fetchTag(cachePath + "minio")
+	if err != nil {
+		return latest, err
+	}
+
+	latest, err = miniov2.ReleaseTagToReleaseTime(tag)
+	if err != nil {
+		return latest, err
+	}
+
+	// link all files with tag specific values in the name.
+	// this is because minio updater looks for files in this name format.
+	filesToLink := map[string]string{
+		"minio":           "minio." + tag,
+		"minio.sha256sum": "minio." + tag + ".sha256sum",
+		"minio.minisig":   "minio." + tag + ".minisig",
+	}
+	for s, d := range filesToLink {
+		if err = linkArtifact(cachePath+s, basePath+d); err != nil {
+			return latest, err
+		}
+	}
+	return latest, nil
+}
+
+// Remove all the files created during upload process
+func (c *Controller) removeArtifacts() error {
+	return os.RemoveAll(updatePath)
+}
+
+// upgradeImage looks up the tenant image in the artifacts directory mounted into the Operator first, so tenants can
+// be upgraded in disconnected clusters. Otherwise the image is pulled from the tenant registry mirror if set, or from
+// the registry of the image.
+func (c *Controller) upgradeImage(tenant *miniov2.Tenant) (v1.Image, error) {
+	image := tenant.TargetImage()
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	if artifactsPath := operatorArtifactsPath(); artifactsPath != "" {
+		img, err := imageFromArtifactsPath(artifactsPath, image, ref)
+		if err == nil {
+			klog.Infof("Using image %s from %s", image, artifactsPath)
+			return img, nil
+		}
+		klog.V(2).Infof("Image %s not available in %s: %v", image, artifactsPath, err)
+	}
+
+	if tenant.Spec.ImageMirror != "" {
+		if ref, err = mirrorReference(tenant.Spec.ImageMirror, tenant.Spec.ImageMirrorInsecure, ref); err != nil {
+			return nil, err
+		}
+	}
+
+	keychain := authn.DefaultKeychain
+
+	// if the tenant has imagePullSecret use that for pulling the image, but if we fail to extract the secret or we
+	// can't find the expected registry in the secret we will continue with the default keychain. This is because the
+	// needed pull secret could be attached to the service-account.
+	if tenant.Spec.ImagePullSecret.Name != "" {
+		// Get the secret
+		keychain, err = c.getKeychainForTenant(context.Background(), ref, tenant)
+		if err != nil {
+			klog.Info(err)
+		}
+	}
+
+	return remote.Image(ref, remote.WithAuthFromKeychain(keychain))
+}
+
+// mirrorReference points the image reference to the registry mirror, keeping its repository and tag or digest.
+// The mirror is reached over HTTPS, insecure mirrors fall back to HTTP as in-cluster registries often don't serve TLS.
+func mirrorReference(mirror string, insecure bool, ref name.Reference) (name.Reference, error) {
+	separator := ":"
+	if _, ok := ref.(name.Digest); ok {
+		separator = "@"
+	}
+	var opts []name.Option
+	if insecure {
+		opts = append(opts, name.Insecure)
+	}
+	return name.ParseReference(fmt.Sprintf("%s/%s%s%s", strings.TrimSuffix(mirror, "/"), ref.Context().RepositoryStr(), separator, ref.Identifier()), opts...)
+}
+
+// imageFromArtifactsPath looks up the image in the artifacts directory. The directory can be an OCI image layout, or
+// hold OCI image layouts and `docker save` tarballs (*.tar).
+func imageFromArtifactsPath(artifactsPath, image string, ref name.Reference) (v1.Image, error) {
+	entries, err := ioutil.ReadDir(artifactsPath)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{artifactsPath}
+	for _, entry := range entries {
+		paths = append(paths, filepath.Join(artifactsPath, entry.Name()))
+	}
+
+	for _, p := range paths {
+		var img v1.Image
+		if _, err = os.Stat(filepath.Join(p, "index.json")); err == nil {
+			img, err = imageFromLayout(p, image, ref)
+		} else if strings.HasSuffix(p, ".tar") {
+			img, err = imageFromTarball(p, ref)
+		} else {
+			continue
+		}
+		if err != nil {
+			klog.V(4).Infof("Image %s not found in %s: %v", image, p, err)
+			continue
+		}
+		return img, nil
+	}
+	return nil, fmt.Errorf("no OCI image layout or tarball holds the image")
+}
+
+// imageFromLayout finds the image in an OCI image layout by digest, or by the name set in the
+// `org.opencontainers.image.ref.name` annotation, which may be the full image name or just its tag
+func imageFromLayout(layoutPath, image string, ref name.Reference) (v1.Image, error) {
+	idx, err := layout.ImageIndexFromPath(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, desc := range manifest.Manifests {
+		if digest, ok := ref.(name.Digest); ok {
+			if desc.Digest.String() != digest.DigestStr() {
+				continue
+			}
+		} else {
+			refName := desc.Annotations[ociRefNameAnnotation]
+			if refName != image && refName != ref.Name() && refName != ref.Identifier() {
+				continue
+			}
+		}
+		switch desc.MediaType {
+		case types.OCIImageIndex, types.DockerManifestList:
+			child, err := idx.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+			return platformImage(child)
+		default:
+			return idx.Image(desc.Digest)
+		}
+	}
+	return nil, fmt.Errorf("image not found in the layout index")
+}
+
+// platformImage returns the linux image of a multi-platform image for the architecture the Operator runs on
+func platformImage(idx v1.ImageIndex) (v1.Image, error) {
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, desc := range manifest.Manifests {
+		if desc.Platform != nil && desc.Platform.OS == "linux" && desc.Platform.Architecture == runtime.GOARCH {
+			return idx.Image(desc.Digest)
+		}
+	}
+	return nil, fmt.Errorf("no linux/%s image found in the index", runtime.GOARCH)
+}
+
+// imageFromTarball finds the image by tag in a tarball written by `docker save`
+func imageFromTarball(tarballPath string, ref name.Reference) (v1.Image, error) {
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return nil, fmt.Errorf("images in tarballs can only be found by tag")
+	}
+	return tarball.ImageFromPath(tarballPath, &tag)
+}
+
+// cacheArtifacts extracts the files needed by the MinIO updater from the image into the cache, unless they were
+// already extracted for the same image digest. It returns the cache directory holding the files.
+func cacheArtifacts(img v1.Image) (string, error) {
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	cachePath := updateCachePath + digest.Hex + slashSeparator
+	if _, err = os.Stat(cachePath); err == nil {
+		klog.V(2).Infof("Using the MinIO binaries cached for image digest %s", digest)
+		now := time.Now()
+		_ = os.Chtimes(cachePath, now, now)
+		return cachePath, nil
+	}
+
+	layer, err := minioLayer(img)
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(updateCachePath, 0755); err != nil {
+		return "", err
+	}
+	// extract in a hidden directory first, so an interrupted extraction never shows up in the cache
+	tmpPath, err := ioutil.TempDir(updateCachePath, "."+digest.Hex)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpPath)
+	}()
+
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	if err = miniov2.ExtractTarReader(minioArtifacts, tmpPath+slashSeparator, rc); err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmpPath, strings.TrimSuffix(cachePath, slashSeparator)); err != nil {
+		// another worker may have cached the same image in the meantime
+		if _, serr := os.Stat(cachePath); serr != nil {
+			return "", err
+		}
+	}
+	pruneArtifactsCache()
+	return cachePath, nil
+}
+
+// minioLayer returns the layer holding the MinIO binaries: the largest layer of the image besides the base layer
+func minioLayer(img v1.Image) (v1.Layer, error) {
+	ls, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+	if len(ls) == 0 {
+		return nil, fmt.Errorf("image has no layers")
+	}
+
+	start := 0
+	if len(ls) >= 2 { // skip the base layer
+		start = 1
+	}
+	layer := ls[start]
+	maxSize, err := layer.Size()
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range ls[start+1:] {
+		s, err := l.Size()
+		if err != nil {
+			return nil, err
+		}
+		if s > maxSize {
+			maxSize = s
+			layer = l
+		}
+	}
+	return layer, nil
+}
+
+// pruneArtifactsCache removes the least recently used images from the cache
+func pruneArtifactsCache() {
+	entries, err := ioutil.ReadDir(updateCachePath)
+	if err != nil {
+		return
+	}
+	var cached []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			cached = append(cached, entry)
+		}
+	}
+	if len(cached) <= updateCacheSize {
+		return
+	}
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].ModTime().After(cached[j].ModTime())
+	})
+	for _, entry := range cached[updateCacheSize:] {
+		klog.V(2).Infof("Removing cached MinIO binaries for image digest %s", entry.Name())
+		_ = os.RemoveAll(updateCachePath + entry.Name())
+	}
+}
+
+// linkArtifact makes a cached file available for download, copying it if it can't be linked
+func linkArtifact(src, dst string) error {
+	_ = os.Remove(dst)
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0777)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,147 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+)
+
+func Test_mirrorReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		mirror   string
+		insecure bool
+		want     string
+		scheme   string
+	}{
+		{
+			name:   "Tag",
+			image:  "minio/minio:RELEASE.2021-08-05T22-01-19Z",
+			mirror: "registry.example.com:5000",
+			want:   "registry.example.com:5000/minio/minio:RELEASE.2021-08-05T22-01-19Z",
+			scheme: "https",
+		},
+		{
+			name:   "Digest",
+			image:  "quay.io/minio/minio@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			mirror: "mirror.example.com/",
+			want:   "mirror.example.com/minio/minio@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			scheme: "https",
+		},
+		{
+			name:     "Insecure",
+			image:    "minio/minio:RELEASE.2021-08-05T22-01-19Z",
+			mirror:   "registry.example.com:5000",
+			insecure: true,
+			want:     "registry.example.com:5000/minio/minio:RELEASE.2021-08-05T22-01-19Z",
+			scheme:   "http",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := name.ParseReference(tt.image)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := mirrorReference(tt.mirror, tt.insecure, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name() != tt.want {
+				t.Errorf("mirrorReference() = %v, want %v", got.Name(), tt.want)
+			}
+			if got.Context().Registry.Scheme() != tt.scheme {
+				t.Errorf("mirrorReference() scheme = %v, want %v", got.Context().Registry.Scheme(), tt.scheme)
+			}
+		})
+	}
+}
+
+func Test_imageFromArtifactsPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	img, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lp, err := layout.Write(filepath.Join(dir, "minio"), empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = lp.AppendImage(img, layout.WithAnnotations(map[string]string{ociRefNameAnnotation: "RELEASE.2021-08-05T22-01-19Z"})); err != nil {
+		t.Fatal(err)
+	}
+	want, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		image   string
+		wantErr bool
+	}{
+		{
+			name:  "Found by tag",
+			image: "minio/minio:RELEASE.2021-08-05T22-01-19Z",
+		},
+		{
+			name:  "Found by digest",
+			image: "minio/minio@" + want.String(),
+		},
+		{
+			name:    "Not found",
+			image:   "minio/minio:RELEASE.2021-07-30T00-02-00Z",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := name.ParseReference(tt.image)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := imageFromArtifactsPath(dir, tt.image, ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("imageFromArtifactsPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			digest, err := got.Digest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest != want {
+				t.Errorf("imageFromArtifactsPath() = %v, want %v", digest, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/minio/madmin-go"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+)
+
+// erasureSet gathers the drives MinIO reports for one erasure set of a pool
+type erasureSet struct {
+	drives  int
+	online  int
+	healing int
+	raw     uint64
+	used    uint64
+}
+
+// setPoolsHealth fills the health and the capacity of every pool of the tenant status from the drives MinIO reports.
+// The drives are matched to the pools through the StatefulSet of the pod serving them.
+func setPoolsHealth(tenant *miniov2.Tenant, storageInfo madmin.StorageInfo) {
+	pools := make(map[string]*miniov2.Pool)
+	serverPools := tenant.ServerPools()
+	for i, ssName := range tenant.ServerPoolStatefulSets() {
+		pools[ssName] = &serverPools[i]
+	}
+
+	sets := make(map[string]map[int]*erasureSet)
+	unindexed := make(map[string]int)
+	for _, disk := range storageInfo.Disks {
+		ssName := driveStatefulSet(disk.Endpoint)
+		if ssName == "" {
+			continue
+		}
+		if sets[ssName] == nil {
+			sets[ssName] = make(map[int]*erasureSet)
+		}
+		setIndex := disk.SetIndex
+		// MinIO versions that don't report the set of a drive leave it at -1, MinIO reports the drives of a pool set
+		// after set, so they are grouped by the number of drives of the erasure sets of the pool
+		if setIndex < 0 {
+			if pool, ok := pools[ssName]; ok && pool.ErasureSetDriveCount() > 0 {
+				setIndex = unindexed[ssName] / pool.ErasureSetDriveCount()
+			}
+			unindexed[ssName]++
+		}
+		set, ok := sets[ssName][setIndex]
+		if !ok {
+			set = &erasureSet{}
+			sets[ssName][setIndex] = set
+		}
+		set.drives++
+		if disk.State == "ok" {
+			set.online++
+		}
+		if disk.Healing {
+			set.healing++
+		}
+		set.raw += disk.TotalSpace
+		set.used += disk.UsedSpace
+	}
+
+	for i := range tenant.Status.Pools {
+		poolStatus := &tenant.Status.Pools[i]
+		poolSets, ok := sets[poolStatus.SSName]
+		if !ok {
+			continue
+		}
+		var raw, usable, used uint64
+		poolStatus.DrivesOnline = 0
+		poolStatus.DrivesOffline = 0
+		poolStatus.DrivesHealing = 0
+		poolStatus.ErasureSets = int32(len(poolSets))
+		poolStatus.ErasureSetsDegraded = 0
+		poolStatus.ErasureSetsQuorumLost = 0
+		for _, set := range poolSets {
+			poolStatus.DrivesOnline += int32(set.online)
+			poolStatus.DrivesOffline += int32(set.drives - set.online)
+			poolStatus.DrivesHealing += int32(set.healing)
+			parity := erasureSetParity(tenant, pools[poolStatus.SSName], storageInfo.Backend.StandardSCParity, set.drives)
+			data := set.drives - parity
+			writeQuorum := data
+			if data == parity {
+				writeQuorum++
+			}
+			switch {
+			case set.online < writeQuorum:
+				poolStatus.ErasureSetsQuorumLost++
+			case set.online < set.drives || set.healing > 0:
+				poolStatus.ErasureSetsDegraded++
+			}
+			raw += set.raw
+			usable += set.raw / uint64(set.drives) * uint64(data)
+			used += set.used
+		}
+		poolStatus.RawCapacity = int64(raw)
+		poolStatus.UsableCapacity = int64(usable)
+		poolStatus.UsedBytes = int64(used)
+		poolStatus.HealthStatus = miniov2.HealthStatusGreen
+		if poolStatus.ErasureSetsDegraded > 0 {
+			poolStatus.HealthStatus = miniov2.HealthStatusYellow
+		}
+		if poolStatus.ErasureSetsQuorumLost > 0 {
+			poolStatus.HealthStatus = miniov2.HealthStatusRed
+		}
+	}
+}
+
+// driveStatefulSet returns the StatefulSet of the pod serving the drive at endpoint, pods are named after their
+// StatefulSet followed by their ordinal
+func driveStatefulSet(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	podName := strings.Split(u.Hostname(), ".")[0]
+	ordinal := strings.LastIndex(podName, "-")
+	if ordinal <= 0 {
+		return ""
+	}
+	return podName[:ordinal]
+}
+
+// erasureSetParity returns the parity of an erasure set of the pool, as reported by MinIO or else as expected from the
+// pool, never more than half the drives of the set
+func erasureSetParity(tenant *miniov2.Tenant, pool *miniov2.Pool, reported, drives int) int {
+	parity := reported
+	if parity <= 0 && pool != nil {
+		parity = tenant.PoolParity(pool)
+	}
+	if parity > drives/2 {
+		parity = drives / 2
+	}
+	return parity
+}
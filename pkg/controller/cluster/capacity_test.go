@@ -0,0 +1,143 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/minio/madmin-go"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_setPoolsHealth(t *testing.T) {
+	drive := func(pod string, set int, state string, healing bool) madmin.Disk {
+		return madmin.Disk{
+			Endpoint:   fmt.Sprintf("https://%s.tenant-hl.ns.svc.cluster.local:9000/export0", pod),
+			State:      state,
+			Healing:    healing,
+			TotalSpace: 1000,
+			UsedSpace:  100,
+			SetIndex:   set,
+		}
+	}
+	storageInfo := madmin.StorageInfo{
+		Disks: []madmin.Disk{
+			// pool-0: a set with one drive offline and a set losing its write quorum
+			drive("tenant-pool-0-0", 0, "ok", false),
+			drive("tenant-pool-0-1", 0, "ok", false),
+			drive("tenant-pool-0-2", 0, "ok", false),
+			drive("tenant-pool-0-3", 0, "offline", false),
+			drive("tenant-pool-0-0", 1, "ok", false),
+			drive("tenant-pool-0-1", 1, "ok", false),
+			drive("tenant-pool-0-2", 1, "offline", false),
+			drive("tenant-pool-0-3", 1, "offline", false),
+			// pool-1: a set with a healing drive
+			drive("tenant-pool-1-0", 0, "ok", true),
+			drive("tenant-pool-1-1", 0, "ok", false),
+			drive("tenant-pool-1-2", 0, "ok", false),
+			drive("tenant-pool-1-3", 0, "ok", false),
+		},
+		Backend: madmin.BackendInfo{StandardSCParity: 2},
+	}
+	tenant := &miniov2.Tenant{Status: miniov2.TenantStatus{Pools: []miniov2.PoolStatus{
+		{SSName: "tenant-pool-0", State: miniov2.PoolInitialized},
+		{SSName: "tenant-pool-1", State: miniov2.PoolInitialized},
+		{SSName: "tenant-pool-2", State: miniov2.PoolCreated},
+	}}}
+
+	setPoolsHealth(tenant, storageInfo)
+
+	expected := []miniov2.PoolStatus{
+		{
+			SSName:                "tenant-pool-0",
+			State:                 miniov2.PoolInitialized,
+			DrivesOnline:          5,
+			DrivesOffline:         3,
+			RawCapacity:           8000,
+			UsableCapacity:        4000,
+			UsedBytes:             800,
+			ErasureSets:           2,
+			ErasureSetsDegraded:   1,
+			ErasureSetsQuorumLost: 1,
+			HealthStatus:          miniov2.HealthStatusRed,
+		},
+		{
+			SSName:              "tenant-pool-1",
+			State:               miniov2.PoolInitialized,
+			DrivesOnline:        4,
+			DrivesHealing:       1,
+			RawCapacity:         4000,
+			UsableCapacity:      2000,
+			UsedBytes:           400,
+			ErasureSets:         1,
+			ErasureSetsDegraded: 1,
+			HealthStatus:        miniov2.HealthStatusYellow,
+		},
+		{SSName: "tenant-pool-2", State: miniov2.PoolCreated},
+	}
+	for i := range expected {
+		if tenant.Status.Pools[i] != expected[i] {
+			t.Errorf("setPoolsHealth() pool %d = %+v, expected %+v", i, tenant.Status.Pools[i], expected[i])
+		}
+	}
+}
+
+func Test_setPoolsHealthWithoutSetIndex(t *testing.T) {
+	// pool-0 runs on a migrated StatefulSet, with 2 erasure sets of 16 drives and the default parity of 4
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Spec:       miniov2.TenantSpec{Pools: []miniov2.Pool{{Name: "pool-0", Servers: 4, VolumesPerServer: 8}}},
+		Status: miniov2.TenantStatus{Pools: []miniov2.PoolStatus{
+			{SSName: "tenant-pool-0-m1", State: miniov2.PoolInitialized},
+		}},
+	}
+	// MinIO reports the drives set after set, the first set loses 5 drives and the second one 4
+	offline := map[int]bool{0: true, 1: true, 2: true, 3: true, 4: true, 16: true, 17: true, 18: true, 19: true}
+	var storageInfo madmin.StorageInfo
+	for i := 0; i < 32; i++ {
+		state := "ok"
+		if offline[i] {
+			state = "offline"
+		}
+		storageInfo.Disks = append(storageInfo.Disks, madmin.Disk{
+			Endpoint:   fmt.Sprintf("https://tenant-pool-0-m1-%d.tenant-hl.ns.svc.cluster.local:9000/export%d", i%4, i/4),
+			State:      state,
+			TotalSpace: 1000,
+			SetIndex:   -1,
+		})
+	}
+
+	setPoolsHealth(tenant, storageInfo)
+
+	expected := miniov2.PoolStatus{
+		SSName:                "tenant-pool-0-m1",
+		State:                 miniov2.PoolInitialized,
+		DrivesOnline:          23,
+		DrivesOffline:         9,
+		RawCapacity:           32000,
+		UsableCapacity:        24000,
+		ErasureSets:           2,
+		ErasureSetsDegraded:   1,
+		ErasureSetsQuorumLost: 1,
+		HealthStatus:          miniov2.HealthStatusRed,
+	}
+	if tenant.Status.Pools[0] != expected {
+		t.Errorf("setPoolsHealth() pool = %+v, expected %+v", tenant.Status.Pools[0], expected)
+	}
+}
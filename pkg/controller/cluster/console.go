@@ -50,7 +50,14 @@ func (c *Controller) checkConsoleCertificatesStatus(ctx context.Context, tenant
 		// AutoCert will generate Console server certificates if user didn't provide any
 		if !tenant.ConsoleExternalCert() {
 			// check if there's already a TLS secret for console
-			_, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, tenant.ConsoleTLSSecretName(), metav1.GetOptions{})
+			secret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, tenant.ConsoleTLSSecretName(), metav1.GetOptions{})
+			// the ingress hostname may have changed since the certificate was issued
+			if host := tenant.ConsoleIngressHost(); err == nil && host != "" && !certificateCoversHosts(secret, []string{host}) {
+				if err = c.deleteCertificate(ctx, tenant, secret.Name, tenant.ConsoleCSRName(), []string{host}); err != nil {
+					return err
+				}
+				return c.checkAndCreateConsoleCSR(ctx, nsName, tenant)
+			}
 			if err != nil {
 				if k8serrors.IsNotFound(err) {
 					if err := c.checkAndCreateConsoleCSR(ctx, nsName, tenant); err != nil {
@@ -161,11 +168,14 @@ func (c *Controller) checkConsoleStatus(ctx context.Context, tenant *miniov2.Ten
 		}
 	}
 
-	var err error
+	rootCAs, err := c.getTenantRootCAs(ctx, tenant)
+	if err != nil {
+		return err
+	}
 	// Make sure that MinIO is up and running to enable MinIO console user.
-	if !tenant.MinIOHealthCheck() {
-		if _, err = c.updateTenantStatus(ctx, tenant, StatusWaitingForReadyState, totalReplicas); err != nil {
-			return err
+	if tenant, err = c.checkMinIOHealth(ctx, tenant, rootCAs); err != nil {
+		if _, uerr := c.updateTenantStatus(ctx, tenant, StatusWaitingForReadyState, totalReplicas); uerr != nil {
+			return uerr
 		}
 		return err
 	}
@@ -186,6 +196,7 @@ func (c *Controller) checkConsoleStatus(ctx context.Context, tenant *miniov2.Ten
 
 	if err := tenant.CreateUsers(adminClnt, userCredentials, skipCreateUsers); err != nil {
 		klog.V(2).Infof("Unable to create MinIO users: %v", err)
+		c.recorder.Event(tenant, v1.EventTypeWarning, UsersCreationFailed, fmt.Sprintf("Unable to create MinIO users: %v", err))
 		return err
 	}
 
@@ -205,20 +216,28 @@ func generateConsoleCryptoData(tenant *miniov2.Tenant) ([]byte, []byte, error) {
 		return nil, nil, err
 	}
 
+	dnsNames := []string{tenant.ConsoleCIServiceName()}
+	// the hostname routed from outside of the cluster, so the ingress can verify the Console certificate
+	if host := tenant.ConsoleIngressHost(); host != "" {
+		dnsNames = append(dnsNames, host)
+	}
+	var csrExtensions []pkix.Extension
+	for _, dnsName := range dnsNames {
+		csrExtensions = append(csrExtensions, pkix.Extension{
+			Id:       nil,
+			Critical: false,
+			Value:    []byte(dnsName),
+		})
+	}
+
 	csrTemplate := x509.CertificateRequest{
 		Subject: pkix.Name{
 			CommonName:   fmt.Sprintf("system:node:%s", tenant.ConsoleCommonName()),
 			Organization: tenant.Spec.CertConfig.OrganizationName,
 		},
 		SignatureAlgorithm: x509.ECDSAWithSHA512,
-		DNSNames:           []string{tenant.ConsoleCIServiceName()},
-		Extensions: []pkix.Extension{
-			{
-				Id:       nil,
-				Critical: false,
-				Value:    []byte(tenant.ConsoleCIServiceName()),
-			},
-		},
+		DNSNames:           dnsNames,
+		Extensions:         csrExtensions,
 	}
 
 	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &csrTemplate, privateKey)
@@ -249,6 +268,8 @@ func (c *Controller) createConsoleCSR(ctx context.Context, tenant *miniov2.Tenan
 	certbytes, err := c.fetchCertificate(ctx, tenant.ConsoleCSRName())
 	if err != nil {
 		klog.Errorf("Unexpected error during the creation of the csr/%s: %v", tenant.ConsoleCSRName(), err)
+		c.recorder.Event(tenant, v1.EventTypeWarning, CertificateFailed, fmt.Sprintf("Unable to get the certificate of csr/%s: %v", tenant.ConsoleCSRName(), err))
+		observeCSRFailure(tenant, certificateConsole)
 		return err
 	}
 
@@ -313,38 +334,16 @@ func (c *Controller) checkConsoleSvc(ctx context.Context, tenant *miniov2.Tenant
 		if k8serrors.IsNotFound(err) {
 			klog.V(2).Infof("Creating a new Cluster IP Service for console %q", nsName)
 			// Create the clusterIP service for the Tenant
-			consoleSvc = services.NewClusterIPForConsole(tenant)
-			consoleSvc, err = c.kubeClientSet.CoreV1().Services(consoleSvc.Namespace).Create(ctx, consoleSvc, metav1.CreateOptions{})
+			_, err = c.createService(ctx, tenant, services.NewClusterIPForConsole(tenant))
 			if err != nil {
 				klog.V(2).Infof(err.Error())
-				return err
 			}
-		} else {
 			return err
 		}
+		return err
 	}
 
-	consoleSvcMatchesSpec := true
-	// compare any other change from what is specified on the tenant
-	expectedSvc := services.NewClusterIPForConsole(tenant)
-	if !equality.Semantic.DeepDerivative(expectedSvc.Spec, consoleSvc.Spec) {
-		// some field set by the operator has changed
-		consoleSvcMatchesSpec = false
-	}
-
-	// check the specification of the Console ClusterIP service
-	if !consoleSvcMatchesSpec {
-		consoleSvc.ObjectMeta.Annotations = expectedSvc.ObjectMeta.Annotations
-		consoleSvc.ObjectMeta.Labels = expectedSvc.ObjectMeta.Labels
-		consoleSvc.Spec.Ports = expectedSvc.Spec.Ports
-		// we can only expose the service, not un-expose it
-		if tenant.Spec.ExposeServices != nil && tenant.Spec.ExposeServices.Console && consoleSvc.Spec.Type != v1.ServiceTypeLoadBalancer {
-			consoleSvc.Spec.Type = v1.ServiceTypeLoadBalancer
-		}
-		_, err = c.kubeClientSet.CoreV1().Services(tenant.Namespace).Update(ctx, consoleSvc, metav1.UpdateOptions{})
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	// push any change from what is specified on the tenant
+	_, err = c.syncService(ctx, tenant, consoleSvc, services.NewClusterIPForConsole(tenant))
+	return err
 }
@@ -83,7 +83,7 @@ func Test_consoleDeploymentMatchesSpec(t *testing.T) {
 										Env: []corev1.EnvVar{
 											{
 												Name:  "CONSOLE_MINIO_SERVER",
-												Value: "https://minio..svc.cluster.local:443",
+												Value: "https://tenant-a..svc.cluster.local:443",
 											},
 											{
 												Name:  "x",
@@ -203,7 +203,7 @@ func Test_consoleDeploymentMatchesSpec(t *testing.T) {
 										Env: []corev1.EnvVar{
 											{
 												Name:  "CONSOLE_MINIO_SERVER",
-												Value: "https://minio..svc.cluster.local:443",
+												Value: "https://tenant-a..svc.cluster.local:443",
 											},
 										},
 										Ports: []corev1.ContainerPort{
@@ -331,7 +331,7 @@ func Test_consoleDeploymentMatchesSpec(t *testing.T) {
 										Env: []corev1.EnvVar{
 											{
 												Name:  "CONSOLE_MINIO_SERVER",
-												Value: "https://minio..svc.cluster.local:443",
+												Value: "https://tenant-a..svc.cluster.local:443",
 											},
 											{
 												Name:  "x",
@@ -500,7 +500,7 @@ func Test_consoleDeploymentMatchesSpec(t *testing.T) {
 										Env: []corev1.EnvVar{
 											{
 												Name:  "CONSOLE_MINIO_SERVER",
-												Value: "https://minio..svc.cluster.local:443",
+												Value: "https://tenant-a..svc.cluster.local:443",
 											},
 											{
 												Name:  "x",
@@ -626,7 +626,7 @@ func Test_consoleDeploymentMatchesSpec(t *testing.T) {
 										Env: []corev1.EnvVar{
 											{
 												Name:  "CONSOLE_MINIO_SERVER",
-												Value: "https://minio..svc.cluster.local:443",
+												Value: "https://tenant-a..svc.cluster.local:443",
 											},
 											{
 												Name:  "x",
@@ -755,7 +755,7 @@ func Test_consoleDeploymentMatchesSpec(t *testing.T) {
 										Env: []corev1.EnvVar{
 											{
 												Name:  "CONSOLE_MINIO_SERVER",
-												Value: "https://minio..svc.cluster.local:443",
+												Value: "https://tenant-a..svc.cluster.local:443",
 											},
 											{
 												Name:  "x",
@@ -18,6 +18,7 @@
 package cluster
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -30,6 +31,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -205,8 +207,11 @@ func (c *Controller) createSecret(ctx context.Context, tenant *miniov2.Tenant, l
 			"public.crt":  certBytes,
 		},
 	}
-	_, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Create(ctx, secret, metav1.CreateOptions{})
-	return err
+	if _, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	c.recorder.Event(tenant, corev1.EventTypeNormal, CertificateIssued, fmt.Sprintf("Certificate issued and stored in secret %s", secretName))
+	return nil
 }
 
 func parseCertificate(r io.Reader) (*x509.Certificate, error) {
@@ -228,3 +233,40 @@ func parseCertificate(r io.Reader) (*x509.Certificate, error) {
 	}
 	return nil, errors.New("found no (non-CA) certificate in any PEM block")
 }
+
+// certificateCoversHosts returns false if the certificate stored in the TLS secret is not valid for every host, a
+// certificate that can't be read is left alone
+func certificateCoversHosts(secret *corev1.Secret, hosts []string) bool {
+	cert, err := parseCertificate(bytes.NewReader(secret.Data["public.crt"]))
+	if err != nil {
+		return true
+	}
+	for _, host := range hosts {
+		covered := false
+		for _, dnsName := range cert.DNSNames {
+			if strings.EqualFold(dnsName, host) {
+				covered = true
+				break
+			}
+		}
+		if !covered && (strings.HasPrefix(host, "*.") || cert.VerifyHostname(host) != nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteCertificate deletes the TLS secret and the CSR of a certificate issued by the Operator, so a new certificate
+// is requested
+func (c *Controller) deleteCertificate(ctx context.Context, tenant *miniov2.Tenant, secretName, csrName string, hosts []string) error {
+	msg := fmt.Sprintf("The certificate of secret %s doesn't cover the hostnames %s, requesting a new certificate", secretName, strings.Join(hosts, ", "))
+	klog.Infof("Tenant '%s/%s': %s", tenant.Namespace, tenant.Name, msg)
+	c.recorder.Event(tenant, corev1.EventTypeNormal, CertificateReissued, msg)
+	if err := c.certClient.CertificateSigningRequests().Delete(ctx, csrName, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Delete(ctx, secretName, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_certificateCoversHosts(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "system:node:tenant.ns.svc.cluster.local"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"tenant.ns.svc.cluster.local", "minio.example.com", "*.minio.example.com"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{Data: map[string][]byte{
+		"public.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}}
+
+	tests := []struct {
+		name     string
+		secret   *corev1.Secret
+		hosts    []string
+		expected bool
+	}{
+		{
+			name:     "No ingress",
+			secret:   secret,
+			expected: true,
+		},
+		{
+			name:     "Hostnames in the certificate",
+			secret:   secret,
+			hosts:    []string{"minio.example.com", "*.minio.example.com"},
+			expected: true,
+		},
+		{
+			name:     "Bucket covered by the wildcard",
+			secret:   secret,
+			hosts:    []string{"bucket.minio.example.com"},
+			expected: true,
+		},
+		{
+			name:     "Hostname changed",
+			secret:   secret,
+			hosts:    []string{"s3.example.com"},
+			expected: false,
+		},
+		{
+			name:     "Bucket DNS enabled",
+			secret:   secret,
+			hosts:    []string{"s3.example.com", "*.s3.example.com"},
+			expected: false,
+		},
+		{
+			name:     "Unreadable certificate",
+			secret:   &corev1.Secret{},
+			hosts:    []string{"s3.example.com"},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := certificateCoversHosts(tt.secret, tt.hosts); actual != tt.expected {
+				t.Errorf("certificateCoversHosts() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/madmin-go"
+	"github.com/minio/minio-go/v7/pkg/signer"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/statefulsets"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// MinIO admin APIs used to decommission pools
+const (
+	adminAPIPoolDecommission = "/minio/admin/v3/pools/decommission"
+	adminAPIPoolCancel       = "/minio/admin/v3/pools/cancel"
+	adminAPIPoolStatus       = "/minio/admin/v3/pools/status"
+)
+
+// Event reasons reported while decommissioning pools
+const (
+	PoolDecommissionStarted  = "PoolDecommissionStarted"
+	PoolDecommissionFailed   = "PoolDecommissionFailed"
+	PoolDecommissionCanceled = "PoolDecommissionCanceled"
+	PoolDecommissioned       = "PoolDecommissioned"
+)
+
+// poolDecommissionInfo is the progress of a pool decommission as reported by MinIO
+type poolDecommissionInfo struct {
+	StartTime   time.Time `json:"startTime"`
+	StartSize   int64     `json:"startSize"`
+	TotalSize   int64     `json:"totalSize"`
+	CurrentSize int64     `json:"currentSize"`
+	Complete    bool      `json:"complete"`
+	Failed      bool      `json:"failed"`
+	Canceled    bool      `json:"canceled"`
+}
+
+// poolAdminStatus is the status of a server pool as reported by MinIO
+type poolAdminStatus struct {
+	ID           int                   `json:"id"`
+	CmdLine      string                `json:"cmdline"`
+	LastUpdate   time.Time             `json:"lastUpdate"`
+	Decommission *poolDecommissionInfo `json:"decommissionInfo,omitempty"`
+}
+
+// markRemovedPools flags the pools removed from the spec for decommissioning. The size of each removed pool is recorded
+// from its StatefulSet so MinIO keeps being started with the pool until all its data is moved to the remaining pools.
+func (c *Controller) markRemovedPools(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	var poolsStatus []miniov2.PoolStatus
+	changed := false
+	for _, poolStatus := range tenant.Status.Pools {
+		if poolStatus.MigratingTo != "" || poolStatus.State == miniov2.PoolDecommissioning || poolStatus.State == miniov2.PoolDecommissioned || poolInSpec(tenant, poolStatus.SSName) {
+			poolsStatus = append(poolsStatus, poolStatus)
+			continue
+		}
+		changed = true
+		// a pool that was never deployed holds no data
+		if poolStatus.State == miniov2.PoolNotCreated {
+			continue
+		}
+		ss, err := c.statefulSetLister.StatefulSets(tenant.Namespace).Get(poolStatus.SSName)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		volumes := int32(len(ss.Spec.VolumeClaimTemplates))
+		if tenant.Spec.SideCars != nil {
+			volumes -= int32(len(tenant.Spec.SideCars.VolumeClaimTemplates))
+		}
+		poolStatus.Name = ss.Spec.Template.ObjectMeta.Labels[miniov2.PoolLabel]
+		poolStatus.Servers = *ss.Spec.Replicas
+		poolStatus.VolumesPerServer = volumes
+		poolStatus.State = miniov2.PoolDecommissioning
+		klog.Infof("Pool %s was removed from Tenant '%s/%s', it will be decommissioned", poolStatus.Name, tenant.Namespace, tenant.Name)
+		poolsStatus = append(poolsStatus, poolStatus)
+	}
+	if !changed {
+		return tenant, nil
+	}
+	tenant.Status.Pools = poolsStatus
+	return c.updatePoolStatus(ctx, tenant)
+}
+
+// poolInSpec returns true if the StatefulSet belongs to one of the pools in the tenant spec
+func poolInSpec(tenant *miniov2.Tenant, ssName string) bool {
+	for i := range tenant.Spec.Pools {
+		if pi := tenant.PoolStatusIndex(&tenant.Spec.Pools[i]); pi >= 0 && tenant.Status.Pools[pi].SSName == ssName {
+			return true
+		}
+	}
+	return false
+}
+
+// decommissionPools drives the decommission of the pools removed from the spec. Once MinIO reports a pool has moved all
+// its data, MinIO is restarted without the pool. Once MinIO no longer serves the pool, the pool StatefulSet is removed
+// and the reclaim policy is applied to its volumes.
+func (c *Controller) decommissionPools(ctx context.Context, tenant *miniov2.Tenant, minioSecret map[string][]byte, adminClnt *madmin.AdminClient) (*miniov2.Tenant, error) {
+	for i := 0; i < len(tenant.Status.Pools); i++ {
+		poolStatus := tenant.Status.Pools[i]
+		switch poolStatus.State {
+		case miniov2.PoolDecommissioning:
+			if poolInSpec(tenant, poolStatus.SSName) {
+				// the pool was added back to the spec
+				if err := c.poolAdminRequest(ctx, tenant, minioSecret, http.MethodPost, adminAPIPoolCancel, c.serverPoolArg(tenant, poolStatus.SSName), nil); err != nil {
+					klog.Warningf("Unable to cancel the decommission of pool %s: %v", poolStatus.Name, err)
+				}
+				c.recorder.Event(tenant, corev1.EventTypeNormal, PoolDecommissionCanceled,
+					fmt.Sprintf("Pool %s was added back to the tenant, decommission canceled", poolStatus.Name))
+				tenant.Status.Pools[i] = miniov2.PoolStatus{SSName: poolStatus.SSName, State: miniov2.PoolInitialized}
+				return c.updatePoolStatus(ctx, tenant)
+			}
+			poolArg := c.serverPoolArg(tenant, poolStatus.SSName)
+			var status poolAdminStatus
+			if err := c.poolAdminRequest(ctx, tenant, minioSecret, http.MethodGet, adminAPIPoolStatus, poolArg, &status); err != nil {
+				return tenant, err
+			}
+			info := status.Decommission
+			switch {
+			case info == nil || info.Failed || info.Canceled:
+				if info != nil {
+					c.recorder.Event(tenant, corev1.EventTypeWarning, PoolDecommissionFailed,
+						fmt.Sprintf("Decommission of pool %s stopped before completion, restarting it", poolStatus.Name))
+				}
+				if err := c.poolAdminRequest(ctx, tenant, minioSecret, http.MethodPost, adminAPIPoolDecommission, poolArg, nil); err != nil {
+					return tenant, err
+				}
+				c.recorder.Event(tenant, corev1.EventTypeNormal, PoolDecommissionStarted,
+					fmt.Sprintf("Decommissioning pool %s", poolStatus.Name))
+			case info.Complete:
+				tenant.Status.Pools[i].State = miniov2.PoolDecommissioned
+				var err error
+				if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
+					return tenant, err
+				}
+				// Restart the services so they fetch the args without the decommissioned pool, the pool is removed on a
+				// later sync once MinIO no longer serves it
+				if restartErr := adminClnt.ServiceRestart(ctx); restartErr != nil {
+					// MinIO keeps serving the pool until it restarts, so the pool keeps its place in the args
+					tenant.Status.Pools[i].State = miniov2.PoolDecommissioning
+					if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
+						return tenant, err
+					}
+					return tenant, restartErr
+				}
+				return tenant, nil
+			default:
+				klog.Infof("Decommissioning pool %s of Tenant '%s/%s', %d of %d bytes left", poolStatus.Name,
+					tenant.Namespace, tenant.Name, info.CurrentSize, info.StartSize)
+			}
+		case miniov2.PoolDecommissioned:
+			served, err := minioServesStatefulSet(ctx, adminClnt, poolStatus.SSName)
+			if err != nil {
+				return tenant, err
+			}
+			if served {
+				return tenant, fmt.Errorf("waiting for MinIO to restart without pool %s", poolStatus.Name)
+			}
+			err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Delete(ctx, poolStatus.SSName, metav1.DeleteOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return tenant, err
+			}
+			pvcs, err := c.listPVCs(ctx, tenant, fmt.Sprintf("%s=%s,%s=%s", miniov2.TenantLabel, tenant.Name, miniov2.PoolLabel, poolStatus.Name))
+			if err != nil {
+				return tenant, err
+			}
+			if err = c.reclaimPVCs(ctx, tenant, statefulSetPVCs(pvcs, poolStatus.SSName)); err != nil {
+				return tenant, err
+			}
+			if poolStatus.MigratingTo != "" {
+				c.recorder.Event(tenant, corev1.EventTypeNormal, PoolMigrated,
+					fmt.Sprintf("Pool %s was migrated to StatefulSet %s, StatefulSet %s was decommissioned and removed", poolStatus.Name, poolStatus.MigratingTo, poolStatus.SSName))
+			} else {
+				c.recorder.Event(tenant, corev1.EventTypeNormal, PoolDecommissioned,
+					fmt.Sprintf("Pool %s was decommissioned and removed", poolStatus.Name))
+			}
+			tenant.Status.Pools = append(tenant.Status.Pools[:i], tenant.Status.Pools[i+1:]...)
+			if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
+				return tenant, err
+			}
+			i--
+		}
+	}
+	return tenant, nil
+}
+
+// minioServesStatefulSet returns true if one of the servers MinIO reports runs in a pod of the StatefulSet
+func minioServesStatefulSet(ctx context.Context, adminClnt *madmin.AdminClient, ssName string) (bool, error) {
+	info, err := adminClnt.ServerInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, server := range info.Servers {
+		if driveStatefulSet("//"+server.Endpoint) == ssName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// serverPoolArg returns the argument MinIO was started with for the pool of the StatefulSet, MinIO identifies pools by it
+func (c *Controller) serverPoolArg(tenant *miniov2.Tenant, ssName string) string {
+	args := statefulsets.GetContainerArgs(tenant, c.hostsTemplate)
+	for i, name := range tenant.ServerPoolStatefulSets() {
+		if name == ssName && i < len(args) {
+			return args[i]
+		}
+	}
+	return ""
+}
+
+// poolAdminRequest calls one of the pool admin APIs of MinIO for the given pool, decoding the response into result if
+// provided. The calls are signed here as they are not part of the madmin client in use.
+func (c *Controller) poolAdminRequest(ctx context.Context, tenant *miniov2.Tenant, minioSecret map[string][]byte, method, apiPath, poolArg string, result interface{}) error {
+	if poolArg == "" {
+		return fmt.Errorf("unable to find the server arguments of the pool")
+	}
+	reqURL := fmt.Sprintf("%s%s?%s", tenant.GetTenantServiceURL(), apiPath, url.Values{"pool": []string{poolArg}}.Encode())
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte{})
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	req = signer.SignV4(*req, string(minioSecret["accesskey"]), string(minioSecret["secretkey"]), "", "")
+
+	rootCAs, err := c.getTenantRootCAs(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	httpClient := &http.Client{
+		Transport: getHealthCheckTransport(rootCAs),
+	}
+	defer httpClient.CloseIdleConnections()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errResp madmin.ErrorResponse
+		if err = json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Message == "" {
+			return fmt.Errorf("%s %s failed with %s", method, apiPath, resp.Status)
+		}
+		return fmt.Errorf("%s %s failed: %s", method, apiPath, strings.TrimSpace(errResp.Message))
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/poddisruptionbudgets"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// checkPoolDisruptionBudgets keeps a PodDisruptionBudget per pool StatefulSet MinIO runs with, so draining nodes never
+// evicts more servers of a pool than its erasure sets can lose without losing write quorum, including the pools being
+// decommissioned or migrated. The budgets of removed pools are deleted.
+func (c *Controller) checkPoolDisruptionBudgets(ctx context.Context, tenant *miniov2.Tenant) error {
+	client := c.kubeClientSet.PolicyV1beta1().PodDisruptionBudgets(tenant.Namespace)
+	expectedPDBs := map[string]*policyv1beta1.PodDisruptionBudget{}
+	pools, ssNames := tenant.ServerPools(), tenant.ServerPoolStatefulSets()
+	for i := range pools {
+		pdb := poddisruptionbudgets.NewForPool(tenant, &pools[i], ssNames[i])
+		expectedPDBs[pdb.Name] = pdb
+	}
+
+	pdbs, err := client.List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(tenant.MinIOPodLabels()).String()})
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		if !metav1.IsControlledBy(pdb, tenant) {
+			continue
+		}
+		expected, ok := expectedPDBs[pdb.Name]
+		if !ok {
+			klog.V(2).Infof("Deleting PodDisruptionBudget %s/%s", pdb.Namespace, pdb.Name)
+			if err = client.Delete(ctx, pdb.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		existing[pdb.Name] = true
+		if equality.Semantic.DeepEqual(expected.Spec, pdb.Spec) {
+			continue
+		}
+		klog.Infof("PodDisruptionBudget %s/%s allows %s unavailable servers, updating it", pdb.Namespace, pdb.Name, expected.Spec.MaxUnavailable.String())
+		pdb = pdb.DeepCopy()
+		pdb.Spec = expected.Spec
+		if _, err = client.Update(ctx, pdb, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	for name, expected := range expectedPDBs {
+		if existing[name] {
+			continue
+		}
+		klog.V(2).Infof("Creating PodDisruptionBudget %s/%s", expected.Namespace, name)
+		_, err = client.Create(ctx, expected, metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			klog.Warningf("PodDisruptionBudget %s/%s already exists and is not managed for the tenant", expected.Namespace, name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_checkPoolDisruptionBudgets(t *testing.T) {
+	ctx := context.Background()
+	// pool-0 is migrated from 2 to 4 servers
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Spec:       miniov2.TenantSpec{Pools: []miniov2.Pool{{Name: "pool-0", Servers: 4, VolumesPerServer: 4}}},
+		Status: miniov2.TenantStatus{Pools: []miniov2.PoolStatus{
+			{SSName: "tenant-pool-0", State: miniov2.PoolInitialized, Name: "pool-0", Servers: 2, VolumesPerServer: 4, MigratingTo: "tenant-pool-0-m1"},
+			{SSName: "tenant-pool-0-m1", State: miniov2.PoolInitialized},
+		}},
+	}
+	c := &Controller{kubeClientSet: fake.NewSimpleClientset()}
+
+	if err := c.checkPoolDisruptionBudgets(ctx, tenant); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"tenant-pool-0":    {"tenant-pool-0-0", "tenant-pool-0-1"},
+		"tenant-pool-0-m1": {"tenant-pool-0-m1-0", "tenant-pool-0-m1-1", "tenant-pool-0-m1-2", "tenant-pool-0-m1-3"},
+	}
+	pdbs, err := c.kubeClientSet.PolicyV1beta1().PodDisruptionBudgets("ns").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pdbs.Items) != len(expected) {
+		t.Fatalf("checkPoolDisruptionBudgets() created %d budgets, expected %d", len(pdbs.Items), len(expected))
+	}
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		podLabels := func(podName string) labels.Set {
+			return labels.Set{miniov2.TenantLabel: "tenant", miniov2.PoolLabel: "pool-0", appsv1.StatefulSetPodNameLabel: podName}
+		}
+		for ssName, podNames := range expected {
+			for _, podName := range podNames {
+				if selected := selector.Matches(podLabels(podName)); selected != (ssName == pdb.Name) {
+					t.Errorf("checkPoolDisruptionBudgets() budget %s selects pod %s = %v", pdb.Name, podName, selected)
+				}
+			}
+		}
+	}
+}
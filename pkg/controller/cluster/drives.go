@@ -0,0 +1,411 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/madmin-go"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// Event reasons reported while tracking offline drives
+const (
+	DriveWentOffline = "DriveOffline"
+	DriveReplaced    = "DriveReplaced"
+	DriveHealed      = "DriveHealed"
+)
+
+// offlineDrive is a drive found offline during a health check
+type offlineDrive struct {
+	pod    string
+	pvc    string
+	reason string
+	// the volume or the node of the drive is gone, it can only come back by being replaced
+	lost bool
+}
+
+// checkDrives looks for drives whose volume or node is gone, or that MinIO reports offline while their pod is running,
+// and keeps track of them in the tenant status. Depending on `spec.autoReplaceDrives`, the drives lost for too long are
+// replaced, drives MinIO reports offline are only reported as they may come back, for example after a node reboot.
+// Replaced drives are tracked until MinIO is done healing them.
+func (c *Controller) checkDrives(ctx context.Context, tenant *miniov2.Tenant, storageInfo madmin.StorageInfo) error {
+	selector := fmt.Sprintf("%s=%s,%s", miniov2.TenantLabel, tenant.Name, miniov2.PoolLabel)
+	podList, err := c.kubeClientSet.CoreV1().Pods(tenant.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	pvcList, err := c.listPVCs(ctx, tenant, selector)
+	if err != nil {
+		return err
+	}
+	pods := make(map[string]*corev1.Pod)
+	for i := range podList.Items {
+		pods[podList.Items[i].Name] = &podList.Items[i]
+	}
+	pvcs := make(map[string]*corev1.PersistentVolumeClaim)
+	for i := range pvcList {
+		pvcs[pvcList[i].Name] = &pvcList[i]
+	}
+
+	found := make(map[string]offlineDrive)
+	// drives whose volume or node is gone
+	hosts := make(map[string]bool)
+	for _, pod := range pods {
+		for _, claim := range minioClaims(tenant, pod) {
+			pvc, ok := pvcs[claim]
+			if !ok {
+				continue
+			}
+			if reason := c.deadVolumeReason(ctx, pvc, hosts); reason != "" {
+				found[claim] = offlineDrive{pod: pod.Name, pvc: claim, reason: reason, lost: true}
+			}
+		}
+	}
+	// drives MinIO reports offline, the drives of a pod that is down are offline until the pod is back
+	for _, disk := range storageInfo.Disks {
+		if disk.State == madmin.DriveStateOk {
+			continue
+		}
+		pod, claim := driveClaim(tenant, pods, disk.Endpoint)
+		if claim == "" || !podRunningAndReady(pods[pod]) {
+			continue
+		}
+		if _, ok := found[claim]; !ok {
+			found[claim] = offlineDrive{pod: pod, pvc: claim, reason: fmt.Sprintf("MinIO reports the drive %s", disk.State)}
+		}
+	}
+
+	var drives []miniov2.DriveStatus
+	for _, drive := range tenant.Status.OfflineDrives {
+		od, offline := found[drive.PVC]
+		delete(found, drive.PVC)
+		switch {
+		case drive.State == miniov2.DriveReplaced:
+			if !offline && tenant.Status.DrivesHealing == 0 {
+				c.recorder.Event(tenant, corev1.EventTypeNormal, DriveHealed,
+					fmt.Sprintf("Drive %s of pod %s was replaced and healed", drive.PVC, drive.Pod))
+				continue
+			}
+		case !offline:
+			klog.Infof("Drive %s of pod %s is back online", drive.PVC, drive.Pod)
+			continue
+		default:
+			drive.State = driveState(od)
+			drive.Reason = od.reason
+		}
+		drives = append(drives, drive)
+	}
+	claims := make([]string, 0, len(found))
+	for claim := range found {
+		claims = append(claims, claim)
+	}
+	sort.Strings(claims)
+	now := metav1.Now()
+	for _, claim := range claims {
+		od := found[claim]
+		c.recorder.Event(tenant, corev1.EventTypeWarning, DriveWentOffline, fmt.Sprintf("Drive %s of pod %s is offline: %s", od.pvc, od.pod, od.reason))
+		drives = append(drives, miniov2.DriveStatus{
+			PVC:          od.pvc,
+			Pod:          od.pod,
+			State:        driveState(od),
+			Reason:       od.reason,
+			OfflineSince: now,
+		})
+	}
+	tenant.Status.OfflineDrives = drives
+
+	if !tenant.AutoReplaceDrives() {
+		return nil
+	}
+	return c.replaceDrives(ctx, tenant, pods)
+}
+
+// driveState returns the state an offline drive is tracked with
+func driveState(od offlineDrive) miniov2.DriveState {
+	if od.lost {
+		return miniov2.DriveLost
+	}
+	return miniov2.DriveOffline
+}
+
+// podRunningAndReady returns true if the pod is running and ready, so the drives MinIO reports offline are not just
+// the drives of a pod that is down
+func podRunningAndReady(pod *corev1.Pod) bool {
+	if pod == nil || pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// replaceDrives deletes the pod and then the PVCs of the drives whose volume or node is gone for longer than the
+// threshold, so the StatefulSet creates them again on healthy storage. The drives of a single pod are replaced at a time, once MinIO is
+// done healing the previously replaced drives, and never while the tenant lost write quorum.
+func (c *Controller) replaceDrives(ctx context.Context, tenant *miniov2.Tenant, pods map[string]*corev1.Pod) error {
+	drives := tenant.Status.OfflineDrives
+	for _, drive := range drives {
+		if drive.State == miniov2.DriveReplaced {
+			klog.V(2).Infof("Waiting for drive %s of Tenant '%s/%s' to heal before replacing other drives", drive.PVC, tenant.Namespace, tenant.Name)
+			return nil
+		}
+	}
+	if tenant.Status.HealthStatus == miniov2.HealthStatusRed {
+		klog.Infof("Tenant '%s/%s' lost write quorum, drives won't be replaced", tenant.Namespace, tenant.Name)
+		return nil
+	}
+
+	threshold := tenant.DriveOfflineThreshold()
+	podName := ""
+	for _, drive := range drives {
+		if drive.State == miniov2.DriveLost && time.Since(drive.OfflineSince.Time) >= threshold {
+			podName = drive.Pod
+			break
+		}
+	}
+	if podName == "" {
+		return nil
+	}
+	if tenant.Paused() {
+		c.recorder.Event(tenant, corev1.EventTypeNormal, ChangeSkipped, fmt.Sprintf("Paused, would replace the offline drives of pod %s", podName))
+		return nil
+	}
+
+	// the PVCs are only released once the pod is gone, a pod stuck on a lost node is removed right away
+	opts := metav1.DeleteOptions{}
+	if pod, ok := pods[podName]; ok && !c.podNodeReady(ctx, pod) {
+		gracePeriod := int64(0)
+		opts.GracePeriodSeconds = &gracePeriod
+	}
+	if err := c.deletePodAndWait(ctx, tenant.Namespace, podName, opts); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	var claims []string
+	for i := range drives {
+		if drives[i].Pod != podName || drives[i].State != miniov2.DriveLost || time.Since(drives[i].OfflineSince.Time) < threshold {
+			continue
+		}
+		klog.Infof("Replacing drive %s of pod %s, offline since %s", drives[i].PVC, podName, drives[i].OfflineSince)
+		err := c.kubeClientSet.CoreV1().PersistentVolumeClaims(tenant.Namespace).Delete(ctx, drives[i].PVC, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		claims = append(claims, drives[i].PVC)
+		drives[i].State = miniov2.DriveReplaced
+		drives[i].ReplacedAt = &now
+		c.recorder.Event(tenant, corev1.EventTypeNormal, DriveReplaced,
+			fmt.Sprintf("Replacing drive %s of pod %s: %s", drives[i].PVC, podName, drives[i].Reason))
+	}
+	return c.releaseReplacedClaims(ctx, tenant.Namespace, podName, claims)
+}
+
+// deletePodAndWait deletes the pod and waits until it's gone, the StatefulSet may have created it again by then
+func (c *Controller) deletePodAndWait(ctx context.Context, namespace, podName string, opts metav1.DeleteOptions) error {
+	pod, err := c.kubeClientSet.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	opts.Preconditions = metav1.NewUIDPreconditions(string(pod.UID))
+	err = c.kubeClientSet.CoreV1().Pods(namespace).Delete(ctx, podName, opts)
+	if err != nil && !k8serrors.IsNotFound(err) && !k8serrors.IsConflict(err) {
+		return err
+	}
+	return wait.PollImmediateUntil(time.Second, func() (bool, error) {
+		current, err := c.kubeClientSet.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return current.UID != pod.UID, nil
+	}, ctx.Done())
+}
+
+// releaseReplacedClaims waits until the deleted PVCs are gone. A pod the StatefulSet created again before the PVCs were
+// deleted keeps them in use, it's deleted so the StatefulSet creates it along with new PVCs.
+func (c *Controller) releaseReplacedClaims(ctx context.Context, namespace, podName string, claims []string) error {
+	return wait.PollImmediateUntil(time.Second, func() (bool, error) {
+		released := true
+		for _, claim := range claims {
+			_, err := c.kubeClientSet.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, claim, metav1.GetOptions{})
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			released = false
+		}
+		if released {
+			return true, nil
+		}
+		pod, err := c.kubeClientSet.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if pod.DeletionTimestamp == nil {
+			klog.Infof("Deleting pod %s again, it was created before its replaced drives were released", podName)
+			err = c.kubeClientSet.CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(pod.UID))})
+			if err != nil && !k8serrors.IsNotFound(err) && !k8serrors.IsConflict(err) {
+				return false, err
+			}
+		}
+		return false, nil
+	}, ctx.Done())
+}
+
+// deadVolumeReason returns why the volume of the PVC is gone, or an empty string if the volume looks fine. The hosts
+// map caches which hostnames were found among the nodes.
+func (c *Controller) deadVolumeReason(ctx context.Context, pvc *corev1.PersistentVolumeClaim, hosts map[string]bool) string {
+	if pvc.Status.Phase == corev1.ClaimLost {
+		return "the PVC lost its volume"
+	}
+	if pvc.Spec.VolumeName == "" {
+		return ""
+	}
+	pv, err := c.kubeClientSet.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Sprintf("volume %s is gone", pvc.Spec.VolumeName)
+		}
+		klog.V(2).Infof("Unable to get volume %s: %v", pvc.Spec.VolumeName, err)
+		return ""
+	}
+	if pv.Status.Phase == corev1.VolumeFailed {
+		return fmt.Sprintf("volume %s failed", pv.Name)
+	}
+
+	// local volumes are pinned to the node they live on
+	volumeHosts := localVolumeHosts(pv)
+	if len(volumeHosts) == 0 {
+		return ""
+	}
+	for _, host := range volumeHosts {
+		exists, ok := hosts[host]
+		if !ok {
+			nodes, err := c.kubeClientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+				LabelSelector: fmt.Sprintf("%s=%s", corev1.LabelHostname, host),
+			})
+			if err != nil {
+				klog.V(2).Infof("Unable to list nodes: %v", err)
+				return ""
+			}
+			exists = len(nodes.Items) > 0
+			hosts[host] = exists
+		}
+		if exists {
+			return ""
+		}
+	}
+	return fmt.Sprintf("node %s of volume %s is gone", strings.Join(volumeHosts, ", "), pv.Name)
+}
+
+// localVolumeHosts returns the hostnames a volume is pinned to through its node affinity
+func localVolumeHosts(pv *corev1.PersistentVolume) []string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return nil
+	}
+	var hosts []string
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == corev1.LabelHostname && expr.Operator == corev1.NodeSelectorOpIn {
+				hosts = append(hosts, expr.Values...)
+			}
+		}
+	}
+	return hosts
+}
+
+// podNodeReady returns true if the node the pod is scheduled on exists and is ready
+func (c *Controller) podNodeReady(ctx context.Context, pod *corev1.Pod) bool {
+	if pod.Spec.NodeName == "" {
+		return true
+	}
+	node, err := c.kubeClientSet.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return !k8serrors.IsNotFound(err)
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// minioClaims returns the PVCs mounted as MinIO drives in the pod, keyed by mount path
+func minioClaims(tenant *miniov2.Tenant, pod *corev1.Pod) map[string]string {
+	claims := make(map[string]string)
+	if len(pod.Spec.Containers) == 0 {
+		return claims
+	}
+	volumes := make(map[string]string)
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			volumes[volume.Name] = volume.PersistentVolumeClaim.ClaimName
+		}
+	}
+	for _, mount := range pod.Spec.Containers[0].VolumeMounts {
+		if claim, ok := volumes[mount.Name]; ok && strings.HasPrefix(mount.MountPath, tenant.Spec.Mountpath) {
+			claims[mount.MountPath] = claim
+		}
+	}
+	return claims
+}
+
+// driveClaim returns the pod and the PVC behind a drive endpoint reported by MinIO, for example
+// https://tenant-pool-0-1.tenant-hl.ns.svc.cluster.local:9000/export1
+func driveClaim(tenant *miniov2.Tenant, pods map[string]*corev1.Pod, endpoint string) (string, string) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return "", ""
+	}
+	podName := strings.Split(u.Hostname(), ".")[0]
+	pod, ok := pods[podName]
+	if !ok {
+		return "", ""
+	}
+	for mountPath, claim := range minioClaims(tenant, pod) {
+		if u.Path == mountPath || strings.HasPrefix(u.Path, mountPath+"/") {
+			return podName, claim
+		}
+	}
+	return "", ""
+}
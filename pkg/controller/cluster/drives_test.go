@@ -0,0 +1,280 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_driveClaim(t *testing.T) {
+	tenant := &miniov2.Tenant{Spec: miniov2.TenantSpec{Mountpath: miniov2.MinIOVolumeMountPath}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-pool-0-1"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "data0", MountPath: "/export0"},
+					{Name: "data1", MountPath: "/export1"},
+					{Name: "configuration", MountPath: "/tmp/minio-config"},
+				},
+			}},
+			Volumes: []corev1.Volume{
+				{Name: "data0", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data0-tenant-pool-0-1"}}},
+				{Name: "data1", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data1-tenant-pool-0-1"}}},
+			},
+		},
+	}
+	pods := map[string]*corev1.Pod{pod.Name: pod}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		wantPod  string
+		wantPVC  string
+	}{
+		{
+			name:     "Drive",
+			endpoint: "https://tenant-pool-0-1.tenant-hl.ns.svc.cluster.local:9000/export1",
+			wantPod:  "tenant-pool-0-1",
+			wantPVC:  "data1-tenant-pool-0-1",
+		},
+		{
+			name:     "Drive with sub path",
+			endpoint: "http://tenant-pool-0-1.tenant-hl.ns.svc.cluster.local:9000/export0/data",
+			wantPod:  "tenant-pool-0-1",
+			wantPVC:  "data0-tenant-pool-0-1",
+		},
+		{
+			name:     "Unknown pod",
+			endpoint: "https://tenant-pool-0-2.tenant-hl.ns.svc.cluster.local:9000/export1",
+		},
+		{
+			name:     "Unknown drive",
+			endpoint: "https://tenant-pool-0-1.tenant-hl.ns.svc.cluster.local:9000/export10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPod, gotPVC := driveClaim(tenant, pods, tt.endpoint)
+			if gotPod != tt.wantPod || gotPVC != tt.wantPVC {
+				t.Errorf("driveClaim() = %v, %v, want %v, %v", gotPod, gotPVC, tt.wantPod, tt.wantPVC)
+			}
+		})
+	}
+}
+
+func Test_replaceDrives(t *testing.T) {
+	ctx := context.Background()
+	pod := func(uid string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "tenant-pool-0-1", Namespace: "ns", UID: types.UID(uid)}}
+	}
+	pvc := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}}
+	}
+	podsResource := corev1.SchemeGroupVersion.WithResource("pods")
+	pvcsResource := corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims")
+
+	kubeClient := fake.NewSimpleClientset(pod("1"), pvc("data0-tenant-pool-0-1"), pvc("data1-tenant-pool-0-1"))
+	var actions []string
+	terminating := make(map[string]bool)
+	recreate := true
+	// PVCs are protected while a pod uses them
+	kubeClient.PrependReactor("delete", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.DeleteAction).GetName()
+		actions = append(actions, "delete pvc "+name)
+		if _, err := kubeClient.Tracker().Get(podsResource, "ns", "tenant-pool-0-1"); err == nil {
+			terminating[name] = true
+			return true, nil, nil
+		}
+		return false, nil, nil
+	})
+	// the StatefulSet creates the pod again right after its first deletion, PVCs are released once the pod is gone
+	kubeClient.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.DeleteAction).GetName()
+		actions = append(actions, "delete pod "+name)
+		if err := kubeClient.Tracker().Delete(podsResource, "ns", name); err != nil {
+			return true, nil, err
+		}
+		for claim := range terminating {
+			if err := kubeClient.Tracker().Delete(pvcsResource, "ns", claim); err != nil {
+				return true, nil, err
+			}
+		}
+		if recreate {
+			recreate = false
+			return true, nil, kubeClient.Tracker().Add(pod("2"))
+		}
+		return true, nil, nil
+	})
+	c := &Controller{kubeClientSet: kubeClient, recorder: record.NewFakeRecorder(10)}
+
+	offlineSince := metav1.NewTime(time.Now().Add(-2 * miniov2.DefaultDriveOfflineThreshold))
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Status: miniov2.TenantStatus{OfflineDrives: []miniov2.DriveStatus{
+			{PVC: "data0-tenant-pool-0-1", Pod: "tenant-pool-0-1", State: miniov2.DriveLost, OfflineSince: offlineSince},
+			{PVC: "data1-tenant-pool-0-1", Pod: "tenant-pool-0-1", State: miniov2.DriveLost, OfflineSince: offlineSince},
+		}},
+	}
+
+	if err := c.replaceDrives(ctx, tenant, map[string]*corev1.Pod{"tenant-pool-0-1": pod("1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"delete pod tenant-pool-0-1",
+		"delete pvc data0-tenant-pool-0-1",
+		"delete pvc data1-tenant-pool-0-1",
+		"delete pod tenant-pool-0-1",
+	}
+	if len(actions) != len(expected) {
+		t.Fatalf("replaceDrives() actions = %v, expected %v", actions, expected)
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Errorf("replaceDrives() actions = %v, expected %v", actions, expected)
+			break
+		}
+	}
+	for _, drive := range tenant.Status.OfflineDrives {
+		if drive.State != miniov2.DriveReplaced {
+			t.Errorf("replaceDrives() drive %s state = %s, expected %s", drive.PVC, drive.State, miniov2.DriveReplaced)
+		}
+		if _, err := kubeClient.CoreV1().PersistentVolumeClaims("ns").Get(ctx, drive.PVC, metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+			t.Errorf("replaceDrives() left PVC %s behind", drive.PVC)
+		}
+	}
+}
+
+func Test_checkDrives(t *testing.T) {
+	ctx := context.Background()
+	labels := map[string]string{miniov2.TenantLabel: "tenant", miniov2.PoolLabel: "pool-0"}
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name     string
+		ready    bool
+		lost     bool
+		tracked  []miniov2.DriveStatus
+		expected map[string]miniov2.DriveState
+	}{
+		{
+			name:     "Pod down",
+			expected: map[string]miniov2.DriveState{},
+		},
+		{
+			name:     "Pod ready",
+			ready:    true,
+			expected: map[string]miniov2.DriveState{"data1-tenant-pool-0-1": miniov2.DriveOffline},
+		},
+		{
+			name:  "Offline for long, only reported",
+			ready: true,
+			tracked: []miniov2.DriveStatus{
+				{PVC: "data1-tenant-pool-0-1", Pod: "tenant-pool-0-1", State: miniov2.DriveOffline, OfflineSince: longAgo},
+			},
+			expected: map[string]miniov2.DriveState{"data1-tenant-pool-0-1": miniov2.DriveOffline},
+		},
+		{
+			name:     "Volume lost",
+			lost:     true,
+			expected: map[string]miniov2.DriveState{"data0-tenant-pool-0-1": miniov2.DriveLost},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-pool-0-1", Namespace: "ns", Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "data0", MountPath: "/export0"},
+							{Name: "data1", MountPath: "/export1"},
+						},
+					}},
+					Volumes: []corev1.Volume{
+						{Name: "data0", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data0-tenant-pool-0-1"}}},
+						{Name: "data1", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data1-tenant-pool-0-1"}}},
+					},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodPending},
+			}
+			if tt.ready {
+				pod.Status = corev1.PodStatus{
+					Phase:      corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				}
+			}
+			pvc := func(name string) *corev1.PersistentVolumeClaim {
+				return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: labels}}
+			}
+			lostPVC := pvc("data0-tenant-pool-0-1")
+			if tt.lost {
+				lostPVC.Status.Phase = corev1.ClaimLost
+			}
+			tenant := &miniov2.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+				Spec: miniov2.TenantSpec{
+					Mountpath:         miniov2.MinIOVolumeMountPath,
+					AutoReplaceDrives: &miniov2.AutoReplaceDrives{Policy: miniov2.DriveReplacementPolicyReplace},
+				},
+				Status: miniov2.TenantStatus{OfflineDrives: tt.tracked},
+			}
+			storageInfo := madmin.StorageInfo{Disks: []madmin.Disk{
+				{Endpoint: "https://tenant-pool-0-1.tenant-hl.ns.svc.cluster.local:9000/export1", State: madmin.DriveStateOffline},
+			}}
+			c := &Controller{
+				kubeClientSet: fake.NewSimpleClientset(pod, lostPVC, pvc("data1-tenant-pool-0-1")),
+				recorder:      record.NewFakeRecorder(10),
+			}
+
+			if err := c.checkDrives(ctx, tenant, storageInfo); err != nil {
+				t.Fatal(err)
+			}
+
+			actual := make(map[string]miniov2.DriveState)
+			for _, drive := range tenant.Status.OfflineDrives {
+				actual[drive.PVC] = drive.State
+			}
+			if len(actual) != len(tt.expected) {
+				t.Fatalf("checkDrives() tracked %v, expected %v", actual, tt.expected)
+			}
+			for claim, state := range tt.expected {
+				if actual[claim] != state {
+					t.Errorf("checkDrives() drive %s = %s, expected %s", claim, actual[claim], state)
+				}
+			}
+			// drives that are only offline are never replaced
+			if _, err := c.kubeClientSet.CoreV1().Pods("ns").Get(ctx, pod.Name, metav1.GetOptions{}); err != nil {
+				t.Errorf("checkDrives() deleted pod %s: %v", pod.Name, err)
+			}
+		})
+	}
+}
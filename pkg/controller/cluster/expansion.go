@@ -0,0 +1,226 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Event reasons reported while expanding the volumes of a pool
+const (
+	VolumeExpansionStarted      = "VolumeExpansionStarted"
+	VolumeExpansionCompleted    = "VolumeExpansionCompleted"
+	VolumeExpansionNotSupported = "VolumeExpansionNotSupported"
+	VolumeShrinkNotSupported    = "VolumeShrinkNotSupported"
+)
+
+// errVolumeExpansionRejected is returned when the volumes of a pool can't move to the size requested by the spec,
+// reprocessing the tenant won't fix it until the spec or the storage class changes
+var errVolumeExpansionRejected = errors.New("the volumes of the pool can't be resized")
+
+// errPoolStatefulSetReleasing is returned while the StatefulSet deleted to expand the pool volumes is still around, the
+// StatefulSet is created again on a later sync once it's gone
+var errPoolStatefulSetReleasing = errors.New("waiting for the pool StatefulSet to be released")
+
+// expandPoolVolumes grows the volumes of an existing pool when the pool volume claim template requests more storage
+// than the pool StatefulSet was created with. The PVCs of the pool are expanded in place and, as the volume claim
+// templates of a StatefulSet can't be updated, the StatefulSet is deleted without touching its pods. Once the
+// StatefulSet is gone, syncHandler creates it again with the new volume size and the StatefulSet adopts the pods.
+func (c *Controller) expandPoolVolumes(ctx context.Context, tenant *miniov2.Tenant, pool *miniov2.Pool, ss *appsv1.StatefulSet) (*miniov2.Tenant, *appsv1.StatefulSet, error) {
+	pi := tenant.PoolStatusIndex(pool)
+	if pool.VolumeClaimTemplate == nil || pi < 0 {
+		return tenant, ss, nil
+	}
+	if ss.DeletionTimestamp != nil && tenant.Status.Pools[pi].ExpandingTo != "" {
+		return tenant, ss, errPoolStatefulSetReleasing
+	}
+	requested, ok := pool.VolumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return tenant, ss, nil
+	}
+	current, ok := poolVolumeRequest(pool, ss)
+	if !ok {
+		return tenant, ss, nil
+	}
+
+	switch requested.Cmp(current) {
+	case 0:
+		if tenant.Status.Pools[pi].ExpandingTo == "" {
+			return tenant, ss, nil
+		}
+		tenant, err := c.poolExpansionProgress(ctx, tenant, pool, ss, requested)
+		return tenant, ss, err
+	case -1:
+		tenant, err := c.rejectVolumeExpansion(ctx, tenant, VolumeShrinkNotSupported, miniov2.ReasonVolumeShrinkNotSupported,
+			fmt.Sprintf("Volumes of pool %s can't shrink from %s to %s", pool.Name, current.String(), requested.String()))
+		return tenant, ss, err
+	}
+
+	pvcs, err := c.poolPVCs(ctx, tenant, pool, ss)
+	if err != nil {
+		return tenant, ss, err
+	}
+	for _, pvc := range pvcs {
+		if err = c.verifyVolumeExpansion(ctx, &pvc); err != nil {
+			tenant, err = c.rejectVolumeExpansion(ctx, tenant, VolumeExpansionNotSupported, miniov2.ReasonVolumeExpansionNotSupported,
+				fmt.Sprintf("Can't expand volumes of pool %s: %v", pool.Name, err))
+			return tenant, ss, err
+		}
+	}
+
+	// record the expansion first, so its progress keeps being reported if the StatefulSet is not created again
+	tenant.Status.Pools[pi].ExpandingTo = requested.String()
+	tenant.Status.Pools[pi].ExpandedVolumes = 0
+	tenant.Status.Pools[pi].TotalVolumes = int32(len(pvcs))
+	if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
+		return tenant, ss, err
+	}
+
+	for _, pvc := range pvcs {
+		if size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; size.Cmp(requested) >= 0 {
+			continue
+		}
+		pvcCopy := pvc.DeepCopy()
+		if pvcCopy.Spec.Resources.Requests == nil {
+			pvcCopy.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvcCopy.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+		if _, err = c.kubeClientSet.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, pvcCopy, metav1.UpdateOptions{}); err != nil {
+			return tenant, ss, err
+		}
+	}
+	c.recorder.Event(tenant, corev1.EventTypeNormal, VolumeExpansionStarted,
+		fmt.Sprintf("Expanding %d volumes of pool %s from %s to %s", len(pvcs), pool.Name, current.String(), requested.String()))
+
+	klog.Infof("Deleting StatefulSet %s to expand its volumes to %s, its pods keep running", ss.Name, requested.String())
+	orphan := metav1.DeletePropagationOrphan
+	err = c.kubeClientSet.AppsV1().StatefulSets(ss.Namespace).Delete(ctx, ss.Name, metav1.DeleteOptions{PropagationPolicy: &orphan})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return tenant, ss, err
+	}
+	return tenant, ss, errPoolStatefulSetReleasing
+}
+
+// rejectVolumeExpansion marks the tenant degraded when the volumes of a pool can't move to the size requested by the
+// spec, the pool keeps its volumes until the spec or the storage class changes
+func (c *Controller) rejectVolumeExpansion(ctx context.Context, tenant *miniov2.Tenant, eventReason, reason, msg string) (*miniov2.Tenant, error) {
+	klog.Warningf("Tenant '%s/%s': %s", tenant.Namespace, tenant.Name, msg)
+	c.recorder.Event(tenant, corev1.EventTypeWarning, eventReason, msg)
+	tenant, err := c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, reason, msg))
+	if err != nil {
+		return tenant, err
+	}
+	return tenant, errVolumeExpansionRejected
+}
+
+// poolVolumeRequest returns the storage requested by the pool volume claim templates of the StatefulSet
+func poolVolumeRequest(pool *miniov2.Pool, ss *appsv1.StatefulSet) (resource.Quantity, bool) {
+	for _, vct := range ss.Spec.VolumeClaimTemplates {
+		if isPoolVolume(pool, vct.Name) {
+			size, ok := vct.Spec.Resources.Requests[corev1.ResourceStorage]
+			return size, ok
+		}
+	}
+	return resource.Quantity{}, false
+}
+
+// isPoolVolume returns true if the volume claim template is one of the MinIO volumes of the pool, not a sidecar volume
+func isPoolVolume(pool *miniov2.Pool, name string) bool {
+	for i := 0; i < int(pool.VolumesPerServer); i++ {
+		if name == fmt.Sprintf("%s%d", pool.VolumeClaimTemplate.Name, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// poolPVCs returns the PVCs of the pool MinIO volumes, named `<volume claim template>-<statefulset>-<ordinal>`
+func (c *Controller) poolPVCs(ctx context.Context, tenant *miniov2.Tenant, pool *miniov2.Pool, ss *appsv1.StatefulSet) ([]corev1.PersistentVolumeClaim, error) {
+	pvcs, err := c.listPVCs(ctx, tenant, fmt.Sprintf("%s=%s,%s=%s", miniov2.TenantLabel, tenant.Name, miniov2.PoolLabel, pool.Name))
+	if err != nil {
+		return nil, err
+	}
+	var poolPVCs []corev1.PersistentVolumeClaim
+	for _, pvc := range statefulSetPVCs(pvcs, ss.Name) {
+		for i := 0; i < int(pool.VolumesPerServer); i++ {
+			if strings.HasPrefix(pvc.Name, fmt.Sprintf("%s%d-%s-", pool.VolumeClaimTemplate.Name, i, ss.Name)) {
+				poolPVCs = append(poolPVCs, pvc)
+				break
+			}
+		}
+	}
+	return poolPVCs, nil
+}
+
+// verifyVolumeExpansion checks the storage class of the PVC allows growing its volume
+func (c *Controller) verifyVolumeExpansion(ctx context.Context, pvc *corev1.PersistentVolumeClaim) error {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return fmt.Errorf("volume %s has no storage class", pvc.Name)
+	}
+	sc, err := c.kubeClientSet.StorageV1().StorageClasses().Get(ctx, *pvc.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion {
+		return fmt.Errorf("storage class %s doesn't allow volume expansion", sc.Name)
+	}
+	return nil
+}
+
+// poolExpansionProgress reports how many pool volumes reached the requested size, clearing the expansion from the pool
+// status once all volumes are expanded
+func (c *Controller) poolExpansionProgress(ctx context.Context, tenant *miniov2.Tenant, pool *miniov2.Pool, ss *appsv1.StatefulSet, size resource.Quantity) (*miniov2.Tenant, error) {
+	pi := tenant.PoolStatusIndex(pool)
+	pvcs, err := c.poolPVCs(ctx, tenant, pool, ss)
+	if err != nil {
+		return tenant, err
+	}
+	expanded := int32(0)
+	for _, pvc := range pvcs {
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok && capacity.Cmp(size) >= 0 {
+			expanded++
+		}
+	}
+
+	poolStatus := &tenant.Status.Pools[pi]
+	if expanded == int32(len(pvcs)) {
+		c.recorder.Event(tenant, corev1.EventTypeNormal, VolumeExpansionCompleted,
+			fmt.Sprintf("All %d volumes of pool %s were expanded to %s", expanded, pool.Name, size.String()))
+		poolStatus.ExpandingTo = ""
+		poolStatus.ExpandedVolumes = 0
+		poolStatus.TotalVolumes = 0
+		return c.updatePoolStatus(ctx, tenant)
+	}
+	if poolStatus.ExpandedVolumes == expanded && poolStatus.TotalVolumes == int32(len(pvcs)) {
+		return tenant, nil
+	}
+	klog.Infof("Expanding volumes of pool %s, %d of %d volumes expanded to %s", pool.Name, expanded, len(pvcs), size.String())
+	poolStatus.ExpandedVolumes = expanded
+	poolStatus.TotalVolumes = int32(len(pvcs))
+	return c.updatePoolStatus(ctx, tenant)
+}
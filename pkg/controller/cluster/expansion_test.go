@@ -0,0 +1,185 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	fakeminio "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_expandPoolVolumes(t *testing.T) {
+	claim := func(size string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+	pvc := func(name string) *corev1.PersistentVolumeClaim {
+		storageClass := "standard"
+		volume := claim("10Gi")
+		volume.ObjectMeta = metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels:    map[string]string{miniov2.TenantLabel: "tenant", miniov2.PoolLabel: "pool-0"},
+		}
+		volume.Spec.StorageClassName = &storageClass
+		return &volume
+	}
+
+	tests := []struct {
+		name              string
+		requested         string
+		allowExpansion    bool
+		expectedErr       error
+		expectedReason    string
+		expectedSize      string
+		expectedDeleted   bool
+		expectedExpanding string
+	}{
+		{
+			name:         "Same size",
+			requested:    "10Gi",
+			expectedSize: "10Gi",
+		},
+		{
+			name:           "Shrink",
+			requested:      "5Gi",
+			allowExpansion: true,
+			expectedErr:    errVolumeExpansionRejected,
+			expectedReason: miniov2.ReasonVolumeShrinkNotSupported,
+			expectedSize:   "10Gi",
+		},
+		{
+			name:           "Storage class without expansion",
+			requested:      "20Gi",
+			expectedErr:    errVolumeExpansionRejected,
+			expectedReason: miniov2.ReasonVolumeExpansionNotSupported,
+			expectedSize:   "10Gi",
+		},
+		{
+			name:              "Expansion",
+			requested:         "20Gi",
+			allowExpansion:    true,
+			expectedErr:       errPoolStatefulSetReleasing,
+			expectedSize:      "20Gi",
+			expectedDeleted:   true,
+			expectedExpanding: "20Gi",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			volumeClaim := claim(tt.requested)
+			tenant := &miniov2.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+				Spec: miniov2.TenantSpec{Pools: []miniov2.Pool{
+					{Name: "pool-0", Servers: 1, VolumesPerServer: 2, VolumeClaimTemplate: &volumeClaim},
+				}},
+				Status: miniov2.TenantStatus{Pools: []miniov2.PoolStatus{
+					{SSName: "tenant-pool-0", State: miniov2.PoolInitialized},
+				}},
+			}
+			ss := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-pool-0", Namespace: "ns"},
+				Spec: appsv1.StatefulSetSpec{
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim("10Gi"), claim("10Gi")},
+				},
+			}
+			ss.Spec.VolumeClaimTemplates[0].Name = "data0"
+			ss.Spec.VolumeClaimTemplates[1].Name = "data1"
+			storageClass := &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "standard"},
+				AllowVolumeExpansion: &tt.allowExpansion,
+			}
+			c := &Controller{
+				kubeClientSet:  fake.NewSimpleClientset(ss, storageClass, pvc("data0-tenant-pool-0-0"), pvc("data1-tenant-pool-0-0")),
+				minioClientSet: fakeminio.NewSimpleClientset(tenant),
+				recorder:       record.NewFakeRecorder(10),
+			}
+
+			tenant, _, err := c.expandPoolVolumes(ctx, tenant, &tenant.Spec.Pools[0], ss)
+			if err != tt.expectedErr {
+				t.Fatalf("expandPoolVolumes() error = %v, expected %v", err, tt.expectedErr)
+			}
+
+			degraded := meta.FindStatusCondition(tenant.Status.Conditions, miniov2.TenantConditionDegraded)
+			switch {
+			case tt.expectedReason == "" && degraded != nil:
+				t.Errorf("expandPoolVolumes() marked the tenant degraded: %s", degraded.Message)
+			case tt.expectedReason != "" && (degraded == nil || degraded.Reason != tt.expectedReason):
+				t.Errorf("expandPoolVolumes() Degraded condition = %+v, expected reason %s", degraded, tt.expectedReason)
+			}
+			if tenant.Status.Pools[0].ExpandingTo != tt.expectedExpanding {
+				t.Errorf("expandPoolVolumes() expandingTo = %s, expected %s", tenant.Status.Pools[0].ExpandingTo, tt.expectedExpanding)
+			}
+
+			for i := 0; i < 2; i++ {
+				name := fmt.Sprintf("data%d-tenant-pool-0-0", i)
+				volume, err := c.kubeClientSet.CoreV1().PersistentVolumeClaims("ns").Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				size := volume.Spec.Resources.Requests[corev1.ResourceStorage]
+				if size.String() != tt.expectedSize {
+					t.Errorf("expandPoolVolumes() volume %s = %s, expected %s", name, size.String(), tt.expectedSize)
+				}
+			}
+
+			_, err = c.kubeClientSet.AppsV1().StatefulSets("ns").Get(ctx, "tenant-pool-0", metav1.GetOptions{})
+			if deleted := k8serrors.IsNotFound(err); deleted != tt.expectedDeleted {
+				t.Errorf("expandPoolVolumes() deleted the StatefulSet = %v, expected %v", deleted, tt.expectedDeleted)
+			}
+		})
+	}
+}
+
+func Test_expandPoolVolumesWhileReleasing(t *testing.T) {
+	volumeClaim := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data"}}
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Spec: miniov2.TenantSpec{Pools: []miniov2.Pool{
+			{Name: "pool-0", Servers: 1, VolumesPerServer: 2, VolumeClaimTemplate: &volumeClaim},
+		}},
+		Status: miniov2.TenantStatus{Pools: []miniov2.PoolStatus{
+			{SSName: "tenant-pool-0", State: miniov2.PoolInitialized, ExpandingTo: "20Gi"},
+		}},
+	}
+	deletedAt := metav1.Now()
+	ss := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "tenant-pool-0", Namespace: "ns", DeletionTimestamp: &deletedAt}}
+	c := &Controller{}
+
+	if _, _, err := c.expandPoolVolumes(context.Background(), tenant, &tenant.Spec.Pools[0], ss); err != errPoolStatefulSetReleasing {
+		t.Errorf("expandPoolVolumes() error = %v, expected %v", err, errPoolStatefulSetReleasing)
+	}
+}
@@ -0,0 +1,230 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// volumeSnapshotResource is the resource of the CSI snapshot API used to snapshot pool volumes
+var volumeSnapshotResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// ErrSnapshotsNotReady is returned while the volume snapshots of a deleted Tenant are not ready to use
+var ErrSnapshotsNotReady = errors.New("Volume snapshots are not ready to use")
+
+// ensureTenantFinalizer adds the operator finalizer to the tenant if it's not there yet
+func (c *Controller) ensureTenantFinalizer(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	if tenant.HasFinalizer() {
+		return tenant, nil
+	}
+	finalizers := append([]string{}, tenant.Finalizers...)
+	finalizers = append(finalizers, miniov2.TenantFinalizer)
+	return c.patchTenantFinalizers(ctx, tenant, finalizers)
+}
+
+// patchTenantFinalizers replaces the finalizers of the tenant. A patch is used rather than an update so the defaults
+// applied in memory by EnsureDefaults are never persisted to the spec.
+func (c *Controller) patchTenantFinalizers(ctx context.Context, tenant *miniov2.Tenant, finalizers []string) (*miniov2.Tenant, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": tenant.ResourceVersion,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Patch(ctx, tenant.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// finalizeTenant applies the reclaim policy to the pool volumes of a tenant being deleted, removes the cluster
+// scoped resources created for it and finally releases the finalizer so the garbage collector takes care of the rest
+func (c *Controller) finalizeTenant(ctx context.Context, tenant *miniov2.Tenant) error {
+	if !tenant.HasFinalizer() {
+		return nil
+	}
+
+	pvcs, err := c.listPVCs(ctx, tenant, fmt.Sprintf("%s=%s,%s", miniov2.TenantLabel, tenant.Name, miniov2.PoolLabel))
+	if err != nil {
+		return err
+	}
+	if err := c.reclaimPVCs(ctx, tenant, pvcs); err != nil {
+		return err
+	}
+
+	if err := c.deleteTenantCSRs(ctx, tenant); err != nil {
+		return err
+	}
+
+	var finalizers []string
+	for _, f := range tenant.Finalizers {
+		if f != miniov2.TenantFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if _, err := c.patchTenantFinalizers(ctx, tenant, finalizers); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	klog.Infof("Tenant '%s/%s' finalized", tenant.Namespace, tenant.Name)
+	return nil
+}
+
+// listPVCs returns the PVCs in the tenant namespace matching the label selector
+func (c *Controller) listPVCs(ctx context.Context, tenant *miniov2.Tenant, selector string) ([]corev1.PersistentVolumeClaim, error) {
+	pvcs, err := c.kubeClientSet.CoreV1().PersistentVolumeClaims(tenant.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pvcs.Items, nil
+}
+
+// reclaimPVCs applies the reclaim policy of the tenant to the given pool PVCs
+func (c *Controller) reclaimPVCs(ctx context.Context, tenant *miniov2.Tenant, pvcs []corev1.PersistentVolumeClaim) error {
+	switch tenant.Spec.ReclaimPolicy {
+	case miniov2.ReclaimPolicySnapshot:
+		if err := c.snapshotPVCs(ctx, tenant, pvcs); err != nil {
+			return err
+		}
+		return c.deletePVCs(ctx, tenant, pvcs)
+	case miniov2.ReclaimPolicyDelete:
+		return c.deletePVCs(ctx, tenant, pvcs)
+	default:
+		klog.Infof("Retaining %d volumes of Tenant '%s/%s'", len(pvcs), tenant.Namespace, tenant.Name)
+	}
+	return nil
+}
+
+// deletePVCs deletes the pool PVCs of the tenant, the PVC protection keeps them around until the pods are gone
+func (c *Controller) deletePVCs(ctx context.Context, tenant *miniov2.Tenant, pvcs []corev1.PersistentVolumeClaim) error {
+	for _, pvc := range pvcs {
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		klog.Infof("Deleting PVC '%s/%s' of Tenant '%s'", pvc.Namespace, pvc.Name, tenant.Name)
+		err := c.kubeClientSet.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotPVCs takes a VolumeSnapshot of every given pool PVC of the tenant, ErrSnapshotsNotReady is returned until
+// all of them are ready to use
+func (c *Controller) snapshotPVCs(ctx context.Context, tenant *miniov2.Tenant, pvcs []corev1.PersistentVolumeClaim) error {
+	ready := true
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		// PVCs already being deleted were snapshotted on a previous pass
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		snapshots := c.dynamicClient.Resource(volumeSnapshotResource).Namespace(pvc.Namespace)
+		snapshotName := volumeSnapshotName(pvc)
+		snapshot, err := snapshots.Get(ctx, snapshotName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			klog.Infof("Creating VolumeSnapshot '%s/%s' of PVC '%s'", pvc.Namespace, snapshotName, pvc.Name)
+			snapshot, err = snapshots.Create(ctx, newVolumeSnapshot(tenant, pvc, snapshotName), metav1.CreateOptions{})
+		}
+		if err != nil {
+			return err
+		}
+		if msg, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found && msg != "" {
+			return fmt.Errorf("VolumeSnapshot '%s/%s' failed: %s", pvc.Namespace, snapshotName, msg)
+		}
+		if readyToUse, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); !readyToUse {
+			ready = false
+		}
+	}
+	if !ready {
+		return ErrSnapshotsNotReady
+	}
+	return nil
+}
+
+// volumeSnapshotName returns a name unique to the PVC, so a new tenant reusing the PVC names never picks up an old
+// snapshot
+func volumeSnapshotName(pvc *corev1.PersistentVolumeClaim) string {
+	uid := string(pvc.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return fmt.Sprintf("%s-%s", pvc.Name, uid)
+}
+
+// newVolumeSnapshot returns a VolumeSnapshot of the given PVC
+func newVolumeSnapshot(tenant *miniov2.Tenant, pvc *corev1.PersistentVolumeClaim, name string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvc.Name,
+		},
+	}
+	if tenant.Spec.VolumeSnapshotClassName != "" {
+		spec["volumeSnapshotClassName"] = tenant.Spec.VolumeSnapshotClassName
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": volumeSnapshotResource.GroupVersion().String(),
+			"kind":       "VolumeSnapshot",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": pvc.Namespace,
+				"labels": map[string]interface{}{
+					miniov2.TenantLabel: tenant.Name,
+					miniov2.PoolLabel:   pvc.Labels[miniov2.PoolLabel],
+				},
+			},
+			"spec": spec,
+		},
+	}
+}
+
+// deleteTenantCSRs deletes the CertificateSigningRequests created for the tenant. CSRs are cluster scoped, so they
+// can't be garbage collected through the tenant owner reference.
+func (c *Controller) deleteTenantCSRs(ctx context.Context, tenant *miniov2.Tenant) error {
+	csrNames := []string{
+		tenant.MinIOCSRName(),
+		tenant.MinIOClientCSRName(),
+		tenant.KESCSRName(),
+		tenant.ConsoleCSRName(),
+	}
+	for _, csrName := range csrNames {
+		err := c.certClient.CertificateSigningRequests().Delete(ctx, csrName, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
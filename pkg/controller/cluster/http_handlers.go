@@ -36,6 +36,7 @@ import (
 	"github.com/gorilla/mux"
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	"github.com/minio/operator/pkg/resources/services"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -60,8 +61,16 @@ func (c *Controller) BucketSrvHandler(w http.ResponseWriter, r *http.Request) {
 	name := vars["name"]
 	deleteBucket := v.Get("delete")
 
+	// Find the tenant
+	tenant, err := c.tenantsLister.Tenants(namespace).Get(name)
+	if err != nil {
+		klog.Errorf("Unable to lookup tenant:%s/%s for the bucket:%s request. err:%s", namespace, name, bucket, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	secret, err := c.kubeClientSet.CoreV1().Secrets(namespace).Get(r.Context(),
-		miniov2.WebhookSecret, metav1.GetOptions{})
+		tenant.WebhookSecretName(), metav1.GetOptions{})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusForbidden)
 		return
@@ -77,6 +86,17 @@ func (c *Controller) BucketSrvHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if ok {
+		// Several tenants may share the namespace, only the tenant owning the bucket service may remove it
+		svc, err := c.kubeClientSet.CoreV1().Services(namespace).Get(r.Context(), bucket, metav1.GetOptions{})
+		if err != nil {
+			klog.Errorf("failed to get service:%s for tenant:%s/%s, err:%s", bucket, namespace, name, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !metav1.IsControlledBy(svc, tenant) {
+			http.Error(w, fmt.Sprintf("service:%s is not owned by tenant:%s/%s", bucket, namespace, name), http.StatusForbidden)
+			return
+		}
 		if err = c.kubeClientSet.CoreV1().Services(namespace).Delete(r.Context(), bucket, metav1.DeleteOptions{}); err != nil {
 			klog.Errorf("failed to delete service:%s for tenant:%s/%s, err:%s", name, namespace, name, err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -85,14 +105,6 @@ func (c *Controller) BucketSrvHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Find the tenant
-	tenant, err := c.tenantsLister.Tenants(namespace).Get(name)
-	if err != nil {
-		klog.Errorf("Unable to lookup tenant:%s/%s for the bucket:%s request. err:%s", namespace, name, bucket, err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
 	tenant.EnsureDefaults()
 
 	// Validate the MinIO Tenant
@@ -111,8 +123,13 @@ func (c *Controller) BucketSrvHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil && k8serrors.IsAlreadyExists(err) {
 		klog.Infof("Bucket:%s already exists for tenant:%s/%s err:%s ", bucket, namespace, name, err)
 		// This might be a previously failed bucket creation. The service is expected to the be the same as the one
-		// already in place so clear the error.
-		err = nil
+		// already in place so clear the error, unless it belongs to another tenant of the namespace.
+		var existing *corev1.Service
+		existing, err = c.kubeClientSet.CoreV1().Services(namespace).Get(r.Context(), bucket, metav1.GetOptions{})
+		if err == nil && !metav1.IsControlledBy(existing, tenant) {
+			http.Error(w, fmt.Sprintf("bucket:%s is already in use by another tenant in namespace:%s", bucket, namespace), http.StatusConflict)
+			return
+		}
 	}
 	if err != nil {
 		klog.Errorf("Unable to create service for tenant:%s/%s for the bucket:%s request. err:%s", namespace, name, bucket, err)
@@ -136,26 +153,26 @@ func (c *Controller) GetenvHandler(w http.ResponseWriter, r *http.Request) {
 	name := vars["name"]
 	key := vars["key"]
 
-	secret, err := c.kubeClientSet.CoreV1().Secrets(namespace).Get(r.Context(),
-		miniov2.WebhookSecret, metav1.GetOptions{})
+	// Get the Tenant resource with this namespace/name
+	tenant, err := c.tenantsLister.Tenants(namespace).Get(name)
 	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// The Tenant resource may no longer exist, in which case we stop processing.
+			http.Error(w, fmt.Sprintf("Tenant '%s' in work queue no longer exists", key), http.StatusNotFound)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusForbidden)
 		return
 	}
 
-	if err = c.validateRequest(r, secret); err != nil {
+	secret, err := c.kubeClientSet.CoreV1().Secrets(namespace).Get(r.Context(),
+		tenant.WebhookSecretName(), metav1.GetOptions{})
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusForbidden)
 		return
 	}
 
-	// Get the Tenant resource with this namespace/name
-	tenant, err := c.tenantsLister.Tenants(namespace).Get(name)
-	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			// The Tenant resource may no longer exist, in which case we stop processing.
-			http.Error(w, fmt.Sprintf("Tenant '%s' in work queue no longer exists", key), http.StatusNotFound)
-			return
-		}
+	if err = c.validateRequest(r, secret); err != nil {
 		http.Error(w, err.Error(), http.StatusForbidden)
 		return
 	}
@@ -0,0 +1,181 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/ingresses"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+)
+
+// checkIngress routes the MinIO and Console hostnames of `spec.ingress` through an Ingress, or through Gateway API
+// HTTPRoutes when a gateway is set. The objects the tenant no longer asks for are removed.
+func (c *Controller) checkIngress(ctx context.Context, tenant *miniov2.Tenant) error {
+	if tenant.Spec.Ingress == nil {
+		if err := c.deleteIngress(ctx, tenant); err != nil {
+			return err
+		}
+		return c.deleteHTTPRoutes(ctx, tenant, nil)
+	}
+	if tenant.Spec.Ingress.Gateway == nil {
+		if err := c.deleteHTTPRoutes(ctx, tenant, nil); err != nil {
+			return err
+		}
+		return c.syncIngress(ctx, tenant)
+	}
+	if err := c.deleteIngress(ctx, tenant); err != nil {
+		return err
+	}
+	return c.syncHTTPRoutes(ctx, tenant)
+}
+
+// syncIngress creates the Ingress of the tenant, or updates it when it drifted from the tenant spec
+func (c *Controller) syncIngress(ctx context.Context, tenant *miniov2.Tenant) error {
+	expected := ingresses.NewForTenant(tenant)
+	client := c.kubeClientSet.NetworkingV1().Ingresses(tenant.Namespace)
+	ingress, err := client.Get(ctx, expected.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		klog.V(2).Infof("Creating Ingress %s/%s", expected.Namespace, expected.Name)
+		_, err = client.Create(ctx, expected, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if ingressMatchesSpec(ingress, expected) {
+		return nil
+	}
+	klog.Infof("Ingress %s/%s drifted from the tenant specification, updating it", ingress.Namespace, ingress.Name)
+	ingress = ingress.DeepCopy()
+	ingress.Labels = miniov2.MergeMaps(miniov2.MergeMaps(map[string]string{}, ingress.Labels), expected.Labels)
+	ingress.Annotations = miniov2.MergeMaps(miniov2.MergeMaps(map[string]string{}, ingress.Annotations), expected.Annotations)
+	if expected.Spec.IngressClassName == nil {
+		expected.Spec.IngressClassName = ingress.Spec.IngressClassName
+	}
+	ingress.Spec = expected.Spec
+	_, err = client.Update(ctx, ingress, metav1.UpdateOptions{})
+	return err
+}
+
+// ingressMatchesSpec returns true if the ingress carries the labels, annotations and spec of the expected ingress. The
+// spec is compared as a derivative since the default ingress class may be set by the cluster.
+func ingressMatchesSpec(ingress, expected *networkingv1.Ingress) bool {
+	if !equality.Semantic.DeepDerivative(expected.Labels, ingress.Labels) ||
+		!equality.Semantic.DeepDerivative(expected.Annotations, ingress.Annotations) {
+		return false
+	}
+	// a derivative comparison would miss rules and certificates added to the ingress
+	if len(expected.Spec.Rules) != len(ingress.Spec.Rules) || len(expected.Spec.TLS) != len(ingress.Spec.TLS) {
+		return false
+	}
+	return equality.Semantic.DeepDerivative(expected.Spec, ingress.Spec)
+}
+
+// deleteIngress removes the Ingress owned by the tenant, if any
+func (c *Controller) deleteIngress(ctx context.Context, tenant *miniov2.Tenant) error {
+	client := c.kubeClientSet.NetworkingV1().Ingresses(tenant.Namespace)
+	ingress, err := client.Get(ctx, tenant.IngressName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(ingress, tenant) {
+		return nil
+	}
+	klog.V(2).Infof("Deleting Ingress %s/%s", ingress.Namespace, ingress.Name)
+	if err = client.Delete(ctx, ingress.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// syncHTTPRoutes creates the HTTPRoutes of the tenant, or updates them when they drifted from the tenant spec
+func (c *Controller) syncHTTPRoutes(ctx context.Context, tenant *miniov2.Tenant) error {
+	client := c.dynamicClient.Resource(ingresses.HTTPRouteResource).Namespace(tenant.Namespace)
+	expectedRoutes := ingresses.NewHTTPRoutesForTenant(tenant)
+	keep := map[string]bool{}
+	for _, expected := range expectedRoutes {
+		keep[expected.GetName()] = true
+		route, err := client.Get(ctx, expected.GetName(), metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			klog.V(2).Infof("Creating HTTPRoute %s/%s", expected.GetNamespace(), expected.GetName())
+			if _, err = client.Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if httpRouteMatchesSpec(route, expected) {
+			continue
+		}
+		klog.Infof("HTTPRoute %s/%s drifted from the tenant specification, updating it", route.GetNamespace(), route.GetName())
+		route = route.DeepCopy()
+		route.SetLabels(miniov2.MergeMaps(miniov2.MergeMaps(map[string]string{}, route.GetLabels()), expected.GetLabels()))
+		route.SetAnnotations(miniov2.MergeMaps(miniov2.MergeMaps(map[string]string{}, route.GetAnnotations()), expected.GetAnnotations()))
+		route.Object["spec"] = expected.Object["spec"]
+		if _, err = client.Update(ctx, route, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	// a route for a hostname removed from the spec
+	return c.deleteHTTPRoutes(ctx, tenant, keep)
+}
+
+// httpRouteMatchesSpec returns true if the route carries the labels, annotations and spec of the expected route. The
+// spec is compared as a derivative since the Gateway API defaults fields of the routes.
+func httpRouteMatchesSpec(route, expected *unstructured.Unstructured) bool {
+	return equality.Semantic.DeepDerivative(expected.GetLabels(), route.GetLabels()) &&
+		equality.Semantic.DeepDerivative(expected.GetAnnotations(), route.GetAnnotations()) &&
+		equality.Semantic.DeepDerivative(expected.Object["spec"], route.Object["spec"])
+}
+
+// deleteHTTPRoutes removes the HTTPRoutes owned by the tenant but the ones to keep. Clusters without the Gateway API
+// have no route to remove.
+func (c *Controller) deleteHTTPRoutes(ctx context.Context, tenant *miniov2.Tenant, keep map[string]bool) error {
+	client := c.dynamicClient.Resource(ingresses.HTTPRouteResource).Namespace(tenant.Namespace)
+	for _, name := range []string{tenant.MinIOHTTPRouteName(), tenant.ConsoleHTTPRouteName()} {
+		if keep[name] {
+			continue
+		}
+		route, err := client.Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(route, tenant) {
+			continue
+		}
+		klog.V(2).Infof("Deleting HTTPRoute %s/%s", route.GetNamespace(), name)
+		if err = client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
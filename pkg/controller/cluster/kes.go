@@ -37,6 +37,7 @@ import (
 
 	"github.com/minio/operator/pkg/resources/statefulsets"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 
 	"k8s.io/klog/v2"
@@ -105,6 +106,8 @@ func (c *Controller) createKESCSR(ctx context.Context, tenant *miniov2.Tenant) e
 	certbytes, err := c.fetchCertificate(ctx, tenant.KESCSRName())
 	if err != nil {
 		klog.Errorf("Unexpected error during the creation of the csr/%s: %v", tenant.KESCSRName(), err)
+		c.recorder.Event(tenant, corev1.EventTypeWarning, CertificateFailed, fmt.Sprintf("Unable to get the certificate of csr/%s: %v", tenant.KESCSRName(), err))
+		observeCSRFailure(tenant, certificateKES)
 		return err
 	}
 
@@ -209,6 +212,8 @@ func (c *Controller) checkKESStatus(ctx context.Context, tenant *miniov2.Tenant,
 			} else {
 				return err
 			}
+		} else if svc, err = c.syncService(ctx, tenant, svc, services.NewHeadlessForKES(tenant)); err != nil {
+			return err
 		}
 
 		// Get the StatefulSet with the name specified in spec
@@ -256,6 +261,7 @@ func (c *Controller) checkKESStatus(ctx context.Context, tenant *miniov2.Tenant,
 					klog.V(2).Infof(err.Error())
 					return err
 				}
+				c.recorder.Event(tenant, corev1.EventTypeNormal, KESJobCreated, fmt.Sprintf("Created Job %s to create the MinIO key on KES", j.Name))
 			} else {
 				return err
 			}
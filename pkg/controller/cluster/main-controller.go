@@ -24,8 +24,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net"
 	"net/http"
-	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -50,11 +50,11 @@ import (
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	appsinformers "k8s.io/client-go/informers/apps/v1"
 	batchinformers "k8s.io/client-go/informers/batch/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
@@ -76,8 +76,6 @@ import (
 	jwtreq "github.com/dgrijalva/jwt-go/request"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/google/go-containerregistry/pkg/v1/remote"
-	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	clientset "github.com/minio/operator/pkg/client/clientset/versioned"
 	minioscheme "github.com/minio/operator/pkg/client/clientset/versioned/scheme"
@@ -106,6 +104,21 @@ const (
 	MessageResourceSynced = "Tenant synced successfully"
 )
 
+// Event reasons reported while reconciling a tenant
+const (
+	PoolCreated         = "PoolCreated"
+	PoolInitialized     = "PoolInitialized"
+	UpgradeStarted      = "UpgradeStarted"
+	UpgradeCompleted    = "UpgradeCompleted"
+	UpgradeFailed       = "UpgradeFailed"
+	CertificateIssued   = "CertificateIssued"
+	CertificateFailed   = "CertificateFailed"
+	CertificateReissued = "CertificateReissued"
+	KESJobCreated       = "KESJobCreated"
+	UsersCreationFailed = "UsersCreationFailed"
+	HealthStatusChanged = "HealthStatusChanged"
+)
+
 // Standard Status messages for Tenant
 const (
 	StatusInitialized                          = "Initialized"
@@ -132,8 +145,9 @@ const (
 	StatusUpdatingResourceRequirements         = "Updating Resource Requirements"
 	StatusUpdatingAffinity                     = "Updating Pod Affinity"
 	StatusNotOwned                             = "Statefulset not controlled by operator"
-	StatusFailedAlreadyExists                  = "Another MinIO Tenant already exists in the namespace"
 	StatusInconsistentMinIOVersions            = "Different versions across MinIO Pools"
+	StatusRestartingMinIO                      = "Restarting MinIO pods"
+	StatusWaitingSafeRestart                   = "Waiting for MinIO to tolerate a server restart"
 )
 
 // ErrMinIONotReady is the error returned when MinIO is not Ready
@@ -152,6 +166,8 @@ type Controller struct {
 	certClient certapi.CertificatesV1Client
 	// promClient is a clientset for Prometheus service monitor
 	promClient promclientset.Interface
+	// dynamicClient is used for resources we don't have a typed client for, like VolumeSnapshots
+	dynamicClient dynamic.Interface
 	// statefulSetLister is able to list/get StatefulSets from a shared
 	// informer's store.
 	statefulSetLister appslisters.StatefulSetLister
@@ -220,6 +236,7 @@ func NewController(
 	minioClientSet clientset.Interface,
 	certClient certapi.CertificatesV1Client,
 	promClient promclientset.Interface,
+	dynamicClient dynamic.Interface,
 	statefulSetInformer appsinformers.StatefulSetInformer,
 	deploymentInformer appsinformers.DeploymentInformer,
 	jobInformer batchinformers.JobInformer,
@@ -243,6 +260,7 @@ func NewController(
 		minioClientSet:             minioClientSet,
 		certClient:                 certClient,
 		promClient:                 promClient,
+		dynamicClient:              dynamicClient,
 		statefulSetLister:          statefulSetInformer.Lister(),
 		statefulSetListerSynced:    statefulSetInformer.Informer().HasSynced,
 		deploymentLister:           deploymentInformer.Lister(),
@@ -276,8 +294,14 @@ func NewController(
 				// Two different versions of the same Tenant will always have different RVs.
 				return
 			}
+			if healthStatusOnlyChanged(oldTenant, newTenant) {
+				// The health monitor records the health of every Tenant on its own schedule, syncing the
+				// Tenant after every check would do nothing but load the operator.
+				return
+			}
 			controller.enqueueTenant(new)
 		},
+		DeleteFunc: controller.forgetTenant,
 	})
 	// Set up an event handler for when StatefulSet resources change. This
 	// handler will lookup the owner of the given StatefulSet, and if it is
@@ -314,6 +338,20 @@ func NewController(
 		},
 		DeleteFunc: controller.handleObject,
 	})
+
+	// Services are watched so manual changes to the services of a tenant are reverted
+	serviceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			newSvc := new.(*corev1.Service)
+			oldSvc := old.(*corev1.Service)
+			if newSvc.ResourceVersion == oldSvc.ResourceVersion {
+				// Periodic resync will send update events for all known Services.
+				return
+			}
+			controller.handleObject(new)
+		},
+		DeleteFunc: controller.handleObject,
+	})
 	return controller
 }
 
@@ -355,7 +393,7 @@ func generateRandomKey(length int) string {
 
 func (c *Controller) applyOperatorWebhookSecret(ctx context.Context, tenant *miniov2.Tenant) (*v1.Secret, error) {
 	secret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx,
-		miniov2.WebhookSecret, metav1.GetOptions{})
+		tenant.WebhookSecretName(), metav1.GetOptions{})
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			secret = getSecretForTenant(tenant, generateRandomKey(20), generateRandomKey(40))
@@ -406,7 +444,7 @@ func getSecretForTenant(tenant *miniov2.Tenant, accessKey, secretKey string) *v1
 	secret := &corev1.Secret{
 		Type: "Opaque",
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      miniov2.WebhookSecret,
+			Name:      tenant.WebhookSecretName(),
 			Namespace: tenant.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(tenant, schema.GroupVersionKind{
@@ -490,129 +528,22 @@ func (c *Controller) getKeychainForTenant(ctx context.Context, ref name.Referenc
 	}, nil
 }
 
-// Attempts to fetch given image and then extracts and keeps relevant files
-// (minio, minio.sha256sum & minio.minisig) at a pre-defined location (/tmp/webhook/v1/update)
-func (c *Controller) fetchArtifacts(tenant *miniov2.Tenant) (latest time.Time, err error) {
-	basePath := updatePath
-
-	if err = os.MkdirAll(basePath, 1777); err != nil {
-		return latest, err
-	}
-
-	ref, err := name.ParseReference(tenant.Spec.Image)
-	if err != nil {
-		return latest, err
-	}
-
-	keychain := authn.DefaultKeychain
-
-	// if the tenant has imagePullSecret use that for pulling the image, but if we fail to extract the secret or we
-	// can't find the expected registry in the secret we will continue with the default keychain. This is because the
-	// needed pull secret could be attached to the service-account.
-	if tenant.Spec.ImagePullSecret.Name != "" {
-		// Get the secret
-		keychain, err = c.getKeychainForTenant(context.Background(), ref, tenant)
-		if err != nil {
-			klog.Info(err)
-		}
-	}
-
-	img, err := remote.Image(ref, remote.WithAuthFromKeychain(keychain))
-	if err != nil {
-		return latest, err
-	}
-
-	ls, err := img.Layers()
-	if err != nil {
-		return latest, err
-	}
-
-	// Find the file with largest size among all layers.
-	// This is the tar file with all minio relevant files.
-	start := 0
-	if len(ls) >= 2 { // skip the base layer
-		start = 1
-	}
-	maxSizeHash, _ := ls[start].Digest()
-	maxSize, _ := ls[start].Size()
-	for i := range ls {
-		if i < start {
-			continue
-		}
-		s, _ := ls[i].Size()
-		if s > maxSize {
-			maxSize, _ = ls[i].Size()
-			maxSizeHash, _ = ls[i].Digest()
-		}
-	}
-
-	f, err := os.OpenFile(basePath+"image.tar", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0777)
-	if err != nil {
-		return latest, err
-	}
-	defer func() {
-		_ = f.Close()
-	}()
-
-	// Tarball writes a file called image.tar
-	// This file in turn has each container layer present inside in the form `<layer-hash>.tar.gz`
-	if err = tarball.Write(ref, img, f); err != nil {
-		return latest, err
-	}
-
-	// Extract the <layer-hash>.tar.gz file that has minio contents from `image.tar`
-	fileNameToExtract := strings.Split(maxSizeHash.String(), ":")[1] + ".tar.gz"
-	if err = miniov2.ExtractTar([]string{fileNameToExtract}, basePath, "image.tar"); err != nil {
-		return latest, err
-	}
-
-	// Extract the minio update related files (minio, minio.sha256sum and minio.minisig) from `<layer-hash>.tar.gz`
-	if err = miniov2.ExtractTar([]string{"usr/bin/minio", "usr/bin/minio.sha256sum", "usr/bin/minio.minisig"}, basePath, fileNameToExtract); err != nil {
-		return latest, err
-	}
-
-	srcBinary := "minio"
-	srcShaSum := "minio.sha256sum"
-	srcSig := "minio.minisig"
-
-	tag, err := c.fetchTag(basePath + srcBinary)
-	if err != nil {
-		return latest, err
-	}
-
-	latest, err = miniov2.ReleaseTagToReleaseTime(tag)
-	if err != nil {
-		return latest, err
-	}
-
-	destBinary := "minio." + tag
-	destShaSum := "minio." + tag + ".sha256sum"
-	destSig := "minio." + tag + ".minisig"
-	filesToRename := map[string]string{srcBinary: destBinary, srcShaSum: destShaSum, srcSig: destSig}
-
-	// rename all files to add tag specific values in the name.
-	// this is because minio updater looks for files in this name format.
-	for s, d := range filesToRename {
-		if err = os.Rename(basePath+s, basePath+d); err != nil {
-			return latest, err
-		}
-	}
-	return latest, nil
-}
-
-// Remove all the files created during upload process
-func (c *Controller) removeArtifacts() error {
-	return os.RemoveAll(updatePath)
-}
-
 // Start will set up the event handlers for types we are interested in, as well
 // as syncing informer caches and starting workers. It will block until stopCh
 // is closed, at which point it will shutdown the workqueue and wait for
 // workers to finish processing their current work items.
 func (c *Controller) Start(threadiness int, stopCh <-chan struct{}) error {
+	c.StartWebhookServer()
+	return c.Run(threadiness, stopCh)
+}
+
+// StartWebhookServer starts serving the operator webhooks in the background. Every replica of the operator serves
+// them, so the CRD conversion keeps working regardless of which replica holds the leader lease. With operator TLS the
+// server starts once the leader issued the operator certificate.
+func (c *Controller) StartWebhookServer() {
 	go func() {
 		if isOperatorTLS() {
-			publicCertPath, publicKeyPath := c.generateTLSCert()
+			publicCertPath, publicKeyPath := c.waitForOperatorTLSCert()
 			klog.Infof("Starting HTTPS api server")
 			// use those certificates to configure the web server
 			if err := c.ws.ListenAndServeTLS(publicCertPath, publicKeyPath); err != http.ErrServerClosed {
@@ -628,10 +559,18 @@ func (c *Controller) Start(threadiness int, stopCh <-chan struct{}) error {
 			}
 		}
 	}()
+}
 
+// Run issues the operator TLS certificate, waits for the informer caches to sync, then starts the workers and the
+// tenant health monitor. When leader election is enabled only the leader calls Run.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	// Start the informer factories to begin populating the informer caches
 	klog.Info("Starting Tenant controller")
 
+	if isOperatorTLS() {
+		go c.issueOperatorTLSCert(stopCh)
+	}
+
 	// Wait for the caches to be synced before starting workers
 	klog.Info("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh, c.statefulSetListerSynced, c.deploymentListerSynced, c.tenantsSynced); !ok {
@@ -706,7 +645,14 @@ func (c *Controller) processNextWorkItem() bool {
 		klog.V(2).Infof("Key from workqueue: %s", key)
 		// Run the syncHandler, passing it the namespace/name string of the
 		// Tenant resource to be synced.
-		if err := c.syncHandler(key); err != nil {
+		start := time.Now()
+		err := c.syncHandler(key)
+		namespace, name := key2NamespaceName(key)
+		// the metrics of a tenant being deleted are dropped by forgetTenant, recording them would bring them back
+		if !c.tenantDeleted(namespace, name) {
+			observeSync(namespace, name, start, err)
+		}
+		if err != nil {
 			// Put the item back on the workqueue to handle any transient errors.
 			c.workqueue.AddRateLimited(key)
 			return fmt.Errorf("error syncing '%s': %s", key, err.Error())
@@ -763,6 +709,28 @@ func (c *Controller) syncHandler(key string) error {
 		}
 		return nil
 	}
+	// Tenants being deleted only need the reclaim policy applied and their finalizer removed, even while paused so
+	// their deletion doesn't hang
+	if tenant.DeletionTimestamp != nil {
+		return c.finalizeTenant(ctx, tenant)
+	}
+	// The MinIO service name is used for the certificates, the admin clients and the health checks, it must be known
+	// before any of them, also for paused tenants that are still observed and monitored
+	if tenant, err = c.consolidateMinIOServiceName(ctx, tenant); err != nil {
+		return err
+	}
+	// Paused tenants are only observed, the changes that would be made are reported as events
+	if tenant.Paused() {
+		return c.observeTenant(ctx, tenant)
+	}
+	if tenant, err = c.resumeTenant(ctx, tenant); err != nil {
+		return err
+	}
+	// Make sure the finalizer is in place before we create anything on behalf of the tenant
+	if tenant, err = c.ensureTenantFinalizer(ctx, tenant); err != nil {
+		return err
+	}
+
 	// Set any required default values and init Global variables
 	nsName := types.NamespacedName{Namespace: namespace, Name: tenantName}
 
@@ -772,7 +740,9 @@ func (c *Controller) syncHandler(key string) error {
 	if err = tenant.Validate(); err != nil {
 		klog.V(2).Infof(err.Error())
 		var err2 error
-		if _, err2 = c.updateTenantStatus(ctx, tenant, err.Error(), 0); err2 != nil {
+		if tenant, err2 = c.updateTenantStatus(ctx, tenant, err.Error(), 0); err2 != nil {
+			klog.V(2).Infof(err2.Error())
+		} else if _, err2 = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, miniov2.ReasonValidationFailed, err.Error())); err2 != nil {
 			klog.V(2).Infof(err2.Error())
 		}
 		// return nil so we don't re-queue this work item
@@ -808,6 +778,25 @@ func (c *Controller) syncHandler(key string) error {
 	err = c.checkMinIOSCertificatesStatus(ctx, tenant, nsName)
 	if err != nil {
 		klog.V(2).Infof("Error when consolidating tenant service: %v", err)
+		if _, cErr := c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionCertificatesReady, metav1.ConditionFalse, miniov2.ReasonCertificatesNotReady, err.Error())); cErr != nil {
+			klog.V(2).Infof(cErr.Error())
+		}
+		return err
+	}
+	// the certificates of MinIO are verified against the CAs the tenant trusts
+	rootCAs, err := c.getTenantRootCAs(ctx, tenant)
+	if err != nil {
+		klog.V(2).Infof("Error loading the trusted CAs of the tenant: %v", err)
+		if _, cErr := c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionCertificatesReady, metav1.ConditionFalse, miniov2.ReasonCertificatesNotReady, err.Error())); cErr != nil {
+			klog.V(2).Infof(cErr.Error())
+		}
+		return err
+	}
+	certsMessage := "MinIO TLS certificates are in place"
+	if !tenant.TLS() {
+		certsMessage = "TLS is not enabled for this tenant"
+	}
+	if tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionCertificatesReady, metav1.ConditionTrue, miniov2.ReasonCertificatesIssued, certsMessage)); err != nil {
 		return err
 	}
 
@@ -828,34 +817,17 @@ func (c *Controller) syncHandler(key string) error {
 		} else {
 			return err
 		}
-	}
-
-	// List all MinIO Tenants in this namespace.
-	li, err := c.tenantsLister.Tenants(tenant.Namespace).List(labels.NewSelector())
-	if err != nil {
+	} else if _, err = c.syncService(ctx, tenant, hlSvc, services.NewHeadlessForMinIO(tenant)); err != nil {
 		return err
 	}
 
-	// Only 1 minio tenant per namespace allowed.
-	if len(li) > 1 {
-		for _, t := range li {
-			if t.Status.CurrentState != StatusInitialized {
-				if _, err = c.updateTenantStatus(ctx, t, StatusFailedAlreadyExists, 0); err != nil {
-					return err
-				}
-				// return nil so we don't re-queue this work item
-				return nil
-			}
-		}
-	}
-
 	minioSecretName := tenant.Spec.CredsSecret.Name
 	minioSecret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, minioSecretName, gOpts)
 	if err != nil {
 		return err
 	}
 
-	adminClnt, err := tenant.NewMinIOAdmin(minioSecret.Data)
+	adminClnt, err := tenant.NewMinIOAdmin(minioSecret.Data, rootCAs)
 	if err != nil {
 		return err
 	}
@@ -866,6 +838,17 @@ func (c *Controller) syncHandler(key string) error {
 	err = c.checkKESStatus(ctx, tenant, totalReplicas, cOpts, uOpts, nsName)
 	if err != nil {
 		klog.V(2).Infof("Error checking KES state %v", err)
+		if _, cErr := c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionKESReady, metav1.ConditionFalse, miniov2.ReasonNotReady, err.Error())); cErr != nil {
+			klog.V(2).Infof(cErr.Error())
+		}
+		return err
+	}
+	if tenant.HasKESEnabled() {
+		tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionKESReady, metav1.ConditionTrue, miniov2.ReasonDeployed, "KES has been deployed"))
+	} else {
+		tenant, err = c.removeTenantCondition(ctx, tenant, miniov2.TenantConditionKESReady)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -879,7 +862,7 @@ func (c *Controller) syncHandler(key string) error {
 			secret := &corev1.Secret{
 				Type: "Opaque",
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      OperatorTLSSecretName,
+					Name:      tenant.OperatorTLSSecretName(),
 					Namespace: tenant.Namespace,
 					Labels:    tenant.MinIOPodLabels(),
 					OwnerReferences: []metav1.OwnerReference{
@@ -922,14 +905,22 @@ func (c *Controller) syncHandler(key string) error {
 		}
 	}
 
+	// pools removed from the spec must keep running until they are decommissioned
+	if tenant, err = c.markRemovedPools(ctx, tenant); err != nil {
+		return err
+	}
+
 	// Check if this is fresh setup not an expansion.
 	freshSetup := len(tenant.Spec.Pools) == len(tenant.Status.Pools)
-	for i, pool := range tenant.Spec.Pools {
-		// Get the StatefulSet with the name specified in Tenant.status.pools[i].SSName
+	// pools whose volumes can't be resized as requested are left as they are, the rest of the tenant is still reconciled
+	expansionRejected := false
+	for _, pool := range tenant.Spec.Pools {
+		// Get the StatefulSet with the name specified in the status of the pool
 
-		// if this index is in the status of pools use it, else capture the desired name in the status and store it
+		// if the pool is in the status use it, else capture the desired name in the status and store it
 		var ssName string
-		if len(tenant.Status.Pools) > i {
+		i := tenant.PoolStatusIndex(&pool)
+		if i >= 0 {
 			ssName = tenant.Status.Pools[i].SSName
 		} else {
 			ssName = tenant.PoolStatefulsetName(&pool)
@@ -937,32 +928,59 @@ func (c *Controller) syncHandler(key string) error {
 				SSName: ssName,
 				State:  miniov2.PoolNotCreated,
 			})
+			i = len(tenant.Status.Pools) - 1
 			// push updates to status
 			if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
 				return err
 			}
 		}
 		ss, err := c.statefulSetLister.StatefulSets(tenant.Namespace).Get(ssName)
-		if k8serrors.IsNotFound(err) {
+		if err == nil && pool.Servers != *ss.Spec.Replicas {
+			// the servers of a pool can't change, the pool is migrated to a new StatefulSet instead
+			var migrating bool
+			if tenant, migrating, err = c.startPoolMigration(ctx, tenant, &pool, ss, rootCAs); err != nil {
+				return err
+			}
+			if migrating {
+				i = tenant.PoolStatusIndex(&pool)
+				freshSetup = false
+				ss, err = c.statefulSetLister.StatefulSets(tenant.Namespace).Get(tenant.Status.Pools[i].SSName)
+			}
+		}
+		if k8serrors.IsNotFound(err) && tenant.Status.Pools[i].ExpandingTo != "" {
+			// the StatefulSet was deleted to expand the pool volumes, it's created again with the new volume size and
+			// adopts the pods left running
+			ss = statefulsets.NewPool(tenant, secret, &pool, hlSvc.Name, c.hostsTemplate, c.operatorVersion, isOperatorTLS())
+			if ss, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Create(ctx, ss, cOpts); err != nil {
+				return err
+			}
+			klog.Infof("Created StatefulSet %s again to expand the volumes of pool %s to %s", ss.Name, pool.Name, tenant.Status.Pools[i].ExpandingTo)
+		} else if k8serrors.IsNotFound(err) {
 
 			klog.Infof("Deploying pool %s", pool.Name)
 
 			// Check healthcheck for previous pool only if its not a fresh setup,
 			// if they are online before adding this pool.
-			if !freshSetup && !tenant.MinIOHealthCheck() {
-				klog.Infof("Deploying pool failed %s", pool.Name)
-				return ErrMinIONotReady
+			if !freshSetup {
+				if tenant, err = c.checkMinIOHealth(ctx, tenant, rootCAs); err != nil {
+					klog.Infof("Deploying pool failed %s", pool.Name)
+					return err
+				}
 			}
 
 			if tenant, err = c.updateTenantStatus(ctx, tenant, StatusProvisioningStatefulSet, 0); err != nil {
 				return err
 			}
+			if tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionPoolsProvisioned, metav1.ConditionFalse, miniov2.ReasonProvisioningPool, fmt.Sprintf("Provisioning pool %s", pool.Name))); err != nil {
+				return err
+			}
 
 			ss = statefulsets.NewPool(tenant, secret, &pool, hlSvc.Name, c.hostsTemplate, c.operatorVersion, isOperatorTLS())
 			ss, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Create(ctx, ss, cOpts)
 			if err != nil {
 				return err
 			}
+			c.recorder.Event(tenant, corev1.EventTypeNormal, PoolCreated, fmt.Sprintf("Created StatefulSet %s for pool %s", ss.Name, pool.Name))
 
 			// Report the pool is properly created
 			tenant.Status.Pools[i].State = miniov2.PoolCreated
@@ -977,11 +995,14 @@ func (c *Controller) syncHandler(key string) error {
 				adminClnt.ServiceRestart(ctx) //nolint:errcheck
 			}
 		} else {
-			if pool.Servers != *ss.Spec.Replicas {
-				// warn the user that replica count of an existing pool can't be changed
-				if tenant, err = c.updateTenantStatus(ctx, tenant, fmt.Sprintf("Can't modify server count for pool %s", pool.Name), 0); err != nil {
-					return err
+			// Grow the pool volumes if the volume claim template requests more storage
+			if tenant, ss, err = c.expandPoolVolumes(ctx, tenant, &pool, ss); err != nil {
+				if errors.Is(err, errVolumeExpansionRejected) {
+					// the tenant is marked Degraded until the spec or the storage class changes, don't re-queue for it
+					expansionRejected = true
+					continue
 				}
+				return err
 			}
 			// Verify if this pool matches the spec on the tenant (resources, affinity, sidecars, etc)
 			poolMatchesSS, err := poolSSMatchesSpec(tenant, &pool, ss, c.operatorVersion)
@@ -1001,6 +1022,10 @@ func (c *Controller) syncHandler(key string) error {
 
 				ssCopy.Spec.Template = nss.Spec.Template
 				ssCopy.Spec.UpdateStrategy = nss.Spec.UpdateStrategy
+				// with rolling updates the image of existing pools only changes one pool at a time
+				if tenant.Spec.UpgradeStrategy == miniov2.UpgradeStrategyRollingUpdate {
+					ssCopy.Spec.Template.Spec.Containers[0].Image = ss.Spec.Template.Spec.Containers[0].Image
+				}
 
 				if ss.Spec.Template.ObjectMeta.Labels == nil {
 					ssCopy.Spec.Template.ObjectMeta.Labels = make(map[string]string)
@@ -1022,6 +1047,9 @@ func (c *Controller) syncHandler(key string) error {
 				return err
 			}
 			msg := fmt.Sprintf(MessageResourceExists, ss.Name)
+			if tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, miniov2.ReasonNotOwned, msg)); err != nil {
+				return err
+			}
 			c.recorder.Event(tenant, corev1.EventTypeWarning, ErrResourceExists, msg)
 			// return nil so we don't re-queue this work item, this error won't get fixed by reprocessing
 			return nil
@@ -1031,21 +1059,28 @@ func (c *Controller) syncHandler(key string) error {
 		totalReplicas += ss.Status.Replicas
 		images = append(images, ss.Spec.Template.Spec.Containers[0].Image)
 	}
+
+	// protect the write quorum of each pool from voluntary evictions
+	if err = c.checkPoolDisruptionBudgets(ctx, tenant); err != nil {
+		return err
+	}
 	// validate each pool if it's initialized
-	for pi, pool := range tenant.Spec.Pools {
+	for _, pool := range tenant.Spec.Pools {
+		pi := tenant.PoolStatusIndex(&pool)
 		// get a pod for the established statefulset
-		if tenant.Status.Pools[pi].State == miniov2.PoolCreated {
+		if pi >= 0 && tenant.Status.Pools[pi].State == miniov2.PoolCreated {
 			// get a pod for the ss
 			pods, err := c.kubeClientSet.CoreV1().Pods(tenant.Namespace).List(ctx, metav1.ListOptions{
-				LabelSelector: fmt.Sprintf("%s=%s", miniov2.PoolLabel, pool.Name),
+				LabelSelector: fmt.Sprintf("%s=%s,%s=%s", miniov2.TenantLabel, tenant.Name, miniov2.PoolLabel, pool.Name),
 			})
 			if err != nil {
 				klog.Warning("Could not validate state of statefulset for pool", err)
 			}
-			if len(pods.Items) > 0 {
-				ssPod := pods.Items[0]
+			// a migrated pool shares its labels with the StatefulSet it's migrated from
+			if ssPods := statefulSetPods(pods.Items, tenant.Status.Pools[pi].SSName); len(ssPods) > 0 {
+				ssPod := ssPods[0]
 				podAddress := fmt.Sprintf("%s:9000", tenant.MinIOHLPodHostname(ssPod.Name))
-				podAdminClnt, err := tenant.NewMinIOAdminForAddress(podAddress, minioSecret.Data)
+				podAdminClnt, err := tenant.NewMinIOAdminForAddress(podAddress, minioSecret.Data, rootCAs)
 				if err != nil {
 					return err
 				}
@@ -1053,6 +1088,7 @@ func (c *Controller) syncHandler(key string) error {
 				_, err = podAdminClnt.ServerInfo(ctx)
 				// any error means we are not ready, if the call succeeds, the ss is ready
 				if err == nil {
+					c.recorder.Event(tenant, corev1.EventTypeNormal, PoolInitialized, fmt.Sprintf("Pool %s is online", pool.Name))
 					// Report the pool is properly created
 					tenant.Status.Pools[pi].State = miniov2.PoolInitialized
 					// push updates to status
@@ -1066,11 +1102,53 @@ func (c *Controller) syncHandler(key string) error {
 		}
 	}
 
-	// compare all the images across all pools, they should always be the same.
+	// decommission the StatefulSets of migrated pools once the new StatefulSets are initialized
+	if tenant, err = c.migratePools(ctx, tenant); err != nil {
+		return err
+	}
+	if tenant, err = c.decommissionPools(ctx, tenant, minioSecret.Data, adminClnt); err != nil {
+		return err
+	}
+
+	poolsCondition := newTenantCondition(miniov2.TenantConditionPoolsProvisioned, metav1.ConditionTrue, miniov2.ReasonPoolsInitialized, "All pools are online")
+	for _, pool := range tenant.Status.Pools {
+		if pool.MigratingTo != "" {
+			poolsCondition = newTenantCondition(miniov2.TenantConditionPoolsProvisioned, metav1.ConditionFalse, miniov2.ReasonMigratingPool, fmt.Sprintf("Migrating pool %s to StatefulSet %s", pool.Name, pool.MigratingTo))
+			break
+		}
+		if pool.State == miniov2.PoolDecommissioning || pool.State == miniov2.PoolDecommissioned {
+			poolsCondition = newTenantCondition(miniov2.TenantConditionPoolsProvisioned, metav1.ConditionFalse, miniov2.ReasonDecommissioningPool, fmt.Sprintf("Decommissioning pool %s", pool.Name))
+			break
+		}
+		if pool.State != miniov2.PoolInitialized {
+			poolsCondition = newTenantCondition(miniov2.TenantConditionPoolsProvisioned, metav1.ConditionFalse, miniov2.ReasonWaitingForPools, fmt.Sprintf("Waiting for pool %s to come online", pool.SSName))
+			break
+		}
+	}
+	if tenant, err = c.updateTenantConditions(ctx, tenant, poolsCondition); err != nil {
+		return err
+	}
+
+	// roll the pools back to the last known good image if the tenant goes red right after an upgrade
+	var rolledBack bool
+	if tenant, rolledBack, err = c.checkUpgradeHealth(ctx, tenant, images); err != nil {
+		return err
+	}
+	if rolledBack {
+		return nil
+	}
+
+	rollingUpdate := tenant.Spec.UpgradeStrategy == miniov2.UpgradeStrategyRollingUpdate
+
+	// compare all the images across all pools, they should always be the same, unless a rolling update is moving
+	// the pools to the new image one at a time.
 	for _, image := range images {
-		for i := 0; i < len(images); i++ {
+		for i := 0; i < len(images) && !rollingUpdate; i++ {
 			if image != images[i] {
-				if _, err = c.updateTenantStatus(ctx, tenant, StatusInconsistentMinIOVersions, totalReplicas); err != nil {
+				if tenant, err = c.updateTenantStatus(ctx, tenant, StatusInconsistentMinIOVersions, totalReplicas); err != nil {
+					return err
+				}
+				if _, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, miniov2.ReasonInconsistentVersions, StatusInconsistentMinIOVersions)); err != nil {
 					return err
 				}
 				return fmt.Errorf("Pool %d is running incorrect image version, all pools are required to be on the same MinIO version. Attempting update of the inconsistent pool",
@@ -1079,11 +1157,20 @@ func (c *Controller) syncHandler(key string) error {
 		}
 	}
 
-	// In loop above we compared all the versions in all pools.
-	// So comparing tenant.Spec.Image (version to update to) against one value from images slice is fine.
-	if tenant.Spec.Image != images[0] && tenant.Status.CurrentState != StatusUpdatingMinIOVersion {
-		if !tenant.MinIOHealthCheck() {
-			return ErrMinIONotReady
+	if rollingUpdate {
+		// roll the pool StatefulSets to the new image, the tenant stays in the updating state until all pools are done
+		var upgraded bool
+		if tenant, upgraded, err = c.rollingUpgrade(ctx, tenant, totalReplicas); err != nil {
+			return err
+		}
+		if !upgraded {
+			return nil
+		}
+	} else if tenant.TargetImage() != images[0] && tenant.Status.CurrentState != StatusUpdatingMinIOVersion {
+		// In loop above we compared all the versions in all pools.
+		// So comparing tenant.TargetImage() (version to update to) against one value from images slice is fine.
+		if tenant, err = c.checkMinIOHealth(ctx, tenant, rootCAs); err != nil {
+			return err
 		}
 
 		// Images different with the newer state change, continue to verify
@@ -1092,24 +1179,43 @@ func (c *Controller) syncHandler(key string) error {
 		if err != nil {
 			return err
 		}
+		tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionUpgrading, metav1.ConditionTrue, miniov2.ReasonUpgradeInProgress,
+			fmt.Sprintf("Updating MinIO from %s to %s", images[0], tenant.TargetImage())))
+		if err != nil {
+			return err
+		}
+		c.recorder.Event(tenant, corev1.EventTypeNormal, UpgradeStarted, fmt.Sprintf("Updating MinIO from %s to %s", images[0], tenant.TargetImage()))
+		// keep track of the image to roll back to if the tenant doesn't survive the update
+		tenant, err = c.updateUpgradeStatus(ctx, tenant, images[0], tenant.Status.RolledBackImage, tenant.Status.UpgradedAt)
+		if err != nil {
+			return err
+		}
 
 		klog.V(4).Infof("Collecting artifacts for Tenant '%s' to update MinIO from: %s, to: %s",
-			tenantName, images[0], tenant.Spec.Image)
+			tenantName, images[0], tenant.TargetImage())
 
 		latest, err := c.fetchArtifacts(tenant)
 		if err != nil {
 			_ = c.removeArtifacts()
+			c.recorder.Event(tenant, corev1.EventTypeWarning, UpgradeFailed, fmt.Sprintf("Unable to fetch MinIO %s: %v", tenant.TargetImage(), err))
+			observeUpgrade(tenant, upgradeFailed)
+			if _, cErr := c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionUpgrading, metav1.ConditionFalse, miniov2.ReasonUpgradeFailed, err.Error())); cErr != nil {
+				klog.V(2).Infof(cErr.Error())
+			}
 			return err
 		}
-		updateURL, err := tenant.UpdateURL(latest, fmt.Sprintf("http://operator.%s.svc.%s:%s%s",
-			miniov2.GetNSFromFile(), miniov2.GetClusterDomain(),
-			miniov2.WebhookDefaultPort, miniov2.WebhookAPIUpdate,
+		updateURL, err := tenant.UpdateURL(latest, fmt.Sprintf("http://%s%s",
+			net.JoinHostPort(operatorUpdateHost(), miniov2.WebhookDefaultPort), miniov2.WebhookAPIUpdate,
 		))
 		if err != nil {
 			_ = c.removeArtifacts()
 
 			err = fmt.Errorf("Unable to get canonical update URL for Tenant '%s', failed with %v", tenantName, err)
-			if _, terr := c.updateTenantStatus(ctx, tenant, err.Error(), totalReplicas); terr != nil {
+			c.recorder.Event(tenant, corev1.EventTypeWarning, UpgradeFailed, err.Error())
+			observeUpgrade(tenant, upgradeFailed)
+			if tenant, terr := c.updateTenantStatus(ctx, tenant, err.Error(), totalReplicas); terr != nil {
+				return terr
+			} else if _, terr = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionUpgrading, metav1.ConditionFalse, miniov2.ReasonUpgradeFailed, err.Error())); terr != nil {
 				return terr
 			}
 
@@ -1118,18 +1224,20 @@ func (c *Controller) syncHandler(key string) error {
 		}
 
 		klog.V(4).Infof("Updating Tenant %s MinIO version from: %s, to: %s -> URL: %s",
-			tenantName, tenant.Spec.Image, images[0], updateURL)
+			tenantName, tenant.TargetImage(), images[0], updateURL)
 
 		us, err := adminClnt.ServerUpdate(ctx, updateURL)
 		if err != nil {
 			_ = c.removeArtifacts()
 
 			err = fmt.Errorf("Tenant '%s' MinIO update failed with %w", tenantName, err)
-			if _, terr := c.updateTenantStatus(ctx, tenant, err.Error(), totalReplicas); terr != nil {
+			if tenant, terr := c.updateTenantStatus(ctx, tenant, err.Error(), totalReplicas); terr != nil {
+				return terr
+			} else if _, terr = c.rollbackUpgrade(ctx, tenant, err.Error()); terr != nil {
 				return terr
 			}
 
-			// Update failed, nothing needs to be changed in the container
+			// Update failed, the pools are kept on the previous image
 			return err
 		}
 
@@ -1141,8 +1249,10 @@ func (c *Controller) syncHandler(key string) error {
 				tenantName,
 				us.CurrentVersion)
 			klog.Info(msg)
-			if _, terr := c.updateTenantStatus(ctx, tenant, msg, totalReplicas); terr != nil {
+			if tenant, terr := c.updateTenantStatus(ctx, tenant, msg, totalReplicas); terr != nil {
 				return err
+			} else if _, terr = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionUpgrading, metav1.ConditionFalse, miniov2.ReasonUpgradeNotNeeded, msg)); terr != nil {
+				return terr
 			}
 			return nil
 		}
@@ -1158,12 +1268,49 @@ func (c *Controller) syncHandler(key string) error {
 			}
 		}
 
+		// the health window starts now that MinIO runs the new version
+		now := metav1.Now()
+		tenant, err = c.updateUpgradeStatus(ctx, tenant, tenant.Status.LastKnownGoodImage, tenant.Status.RolledBackImage, &now)
+		if err != nil {
+			return err
+		}
+
+		tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionUpgrading, metav1.ConditionFalse, miniov2.ReasonUpgradeCompleted,
+			fmt.Sprintf("MinIO updated from %s to %s", us.CurrentVersion, us.UpdatedVersion)))
+		if err != nil {
+			return err
+		}
+		c.recorder.Event(tenant, corev1.EventTypeNormal, UpgradeCompleted, fmt.Sprintf("MinIO updated from %s to %s", us.CurrentVersion, us.UpdatedVersion))
+		observeUpgrade(tenant, upgradeCompleted)
 	}
 
 	// Check whether console is enabled or if it should be removed and the state of it's service
 	err = c.checkConsoleStatus(ctx, tenant, totalReplicas, adminClnt, cOpts, uOpts, nsName)
 	if err != nil {
 		klog.V(2).Infof("Error checking console state %v", err)
+		if _, cErr := c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionConsoleReady, metav1.ConditionFalse, miniov2.ReasonNotReady, err.Error())); cErr != nil {
+			klog.V(2).Infof(cErr.Error())
+		}
+		return err
+	}
+	if tenant.HasConsoleEnabled() {
+		tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionConsoleReady, metav1.ConditionTrue, miniov2.ReasonDeployed, "Console has been deployed"))
+	} else {
+		tenant, err = c.removeTenantCondition(ctx, tenant, miniov2.TenantConditionConsoleReady)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Route the MinIO and Console hostnames through an Ingress or the Gateway API
+	if err = c.checkIngress(ctx, tenant); err != nil {
+		klog.V(2).Infof("Error checking ingress state %v", err)
+		return err
+	}
+
+	// Isolate the pods of the tenant when it asks for NetworkPolicies
+	if err = c.checkNetworkPolicies(ctx, tenant); err != nil {
+		klog.V(2).Infof("Error checking network policies %v", err)
 		return err
 	}
 
@@ -1194,11 +1341,11 @@ func (c *Controller) syncHandler(key string) error {
 			return err
 		}
 		// Make sure that MinIO is up and running to enable Log Search.
-		if !tenant.MinIOHealthCheck() {
-			if _, err = c.updateTenantStatus(ctx, tenant, StatusWaitingForReadyState, totalReplicas); err != nil {
-				return err
+		if tenant, err = c.checkMinIOHealth(ctx, tenant, rootCAs); err != nil {
+			if _, uerr := c.updateTenantStatus(ctx, tenant, StatusWaitingForReadyState, totalReplicas); uerr != nil {
+				return uerr
 			}
-			return ErrMinIONotReady
+			return err
 		}
 		err = c.checkAndConfigureLogSearchAPI(ctx, tenant, logSecret, adminClnt)
 		if err != nil {
@@ -1234,6 +1381,23 @@ func (c *Controller) syncHandler(key string) error {
 		}
 	}
 
+	// Restart the MinIO pods one at a time if requested, the tenant stays in the restarting state until it's done
+	if tenant.RestartRequested() {
+		if tenant, err = c.rollingRestart(ctx, tenant, totalReplicas); err != nil {
+			return err
+		}
+		if tenant.RestartRequested() {
+			return nil
+		}
+	}
+
+	// Any configuration problem reported earlier is gone if we made it this far
+	if !expansionRejected {
+		if tenant, err = c.resolveTenantDegraded(ctx, tenant); err != nil {
+			return err
+		}
+	}
+
 	// Finally, we update the status block of the Tenant resource to reflect the
 	// current state of the world
 	_, err = c.updateTenantStatus(ctx, tenant, StatusInitialized, totalReplicas)
@@ -1252,6 +1416,42 @@ func (c *Controller) enqueueTenant(obj interface{}) {
 	c.workqueue.AddRateLimited(key)
 }
 
+// enqueueTenantAfter puts the Tenant resource back onto the work queue once the given duration has passed.
+func (c *Controller) enqueueTenantAfter(tenant *miniov2.Tenant, after time.Duration) {
+	key, err := cache.MetaNamespaceKeyFunc(tenant)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	c.workqueue.AddAfter(key, after)
+}
+
+// forgetTenant drops the metrics of a Tenant resource deleted from the cluster. This method should *not* be passed
+// resources of any type other than Tenant.
+func (c *Controller) forgetTenant(obj interface{}) {
+	tenant, ok := obj.(*miniov2.Tenant)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			runtime.HandleError(fmt.Errorf("error decoding object, invalid type"))
+			return
+		}
+		tenant, ok = tombstone.Obj.(*miniov2.Tenant)
+		if !ok {
+			runtime.HandleError(fmt.Errorf("error decoding object tombstone, invalid type"))
+			return
+		}
+		klog.V(4).Infof("Recovered deleted Tenant '%s' from tombstone", tenant.GetName())
+	}
+	deleteTenantMetrics(tenant.Namespace, tenant.Name)
+}
+
+// tenantDeleted returns true if the Tenant resource is gone or being deleted
+func (c *Controller) tenantDeleted(namespace, name string) bool {
+	tenant, err := c.tenantsLister.Tenants(namespace).Get(name)
+	return k8serrors.IsNotFound(err) || (err == nil && tenant.DeletionTimestamp != nil)
+}
+
 // handleObject will take any resource implementing metav1.Object and attempt
 // to find the Tenant resource that 'owns' it. It does this by looking at the
 // objects metadata.ownerReferences field for an appropriate OwnerReference.
@@ -1304,7 +1504,10 @@ func MinIOControllerRateLimiter() queue.RateLimiter {
 
 func (c *Controller) checkAndCreateLogHeadless(ctx context.Context, tenant *miniov2.Tenant) (*corev1.Service, error) {
 	svc, err := c.serviceLister.Services(tenant.Namespace).Get(tenant.LogHLServiceName())
-	if err == nil || !k8serrors.IsNotFound(err) {
+	if err == nil {
+		return c.syncService(ctx, tenant, svc, services.NewHeadlessForLog(tenant))
+	}
+	if !k8serrors.IsNotFound(err) {
 		return svc, err
 	}
 
@@ -1351,13 +1554,17 @@ func (c *Controller) checkAndCreateLogStatefulSet(ctx context.Context, tenant *m
 }
 
 func (c *Controller) checkAndCreateLogSearchAPIService(ctx context.Context, tenant *miniov2.Tenant) error {
-	_, err := c.serviceLister.Services(tenant.Namespace).Get(tenant.LogSearchAPIServiceName())
-	if err == nil || !k8serrors.IsNotFound(err) {
+	svc, err := c.serviceLister.Services(tenant.Namespace).Get(tenant.LogSearchAPIServiceName())
+	if err == nil {
+		_, err = c.syncService(ctx, tenant, svc, services.NewClusterIPForLogSearchAPI(tenant))
+		return err
+	}
+	if !k8serrors.IsNotFound(err) {
 		return err
 	}
 
 	klog.V(2).Infof("Creating a new Log Search API Service for %s", tenant.Namespace)
-	svc := services.NewClusterIPForLogSearchAPI(tenant)
+	svc = services.NewClusterIPForLogSearchAPI(tenant)
 	_, err = c.kubeClientSet.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
 	return err
 }
@@ -1481,7 +1688,10 @@ func (c *Controller) checkAndCreatePrometheusConfigMap(ctx context.Context, tena
 
 func (c *Controller) checkAndCreatePrometheusHeadless(ctx context.Context, tenant *miniov2.Tenant) (*corev1.Service, error) {
 	svc, err := c.serviceLister.Services(tenant.Namespace).Get(tenant.PrometheusHLServiceName())
-	if err == nil || !k8serrors.IsNotFound(err) {
+	if err == nil {
+		return c.syncService(ctx, tenant, svc, services.NewHeadlessForPrometheus(tenant))
+	}
+	if !k8serrors.IsNotFound(err) {
 		return svc, err
 	}
 
@@ -0,0 +1,254 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"net/http"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	queue "k8s.io/client-go/util/workqueue"
+)
+
+const metricsNamespace = "minio_operator"
+
+// Certificates issued by the Operator through the Kubernetes CSR API, used to label the CSR failures
+const (
+	certificateMinIO       = "minio"
+	certificateMinIOClient = "minio-client"
+	certificateConsole     = "console"
+	certificateKES         = "kes"
+)
+
+// Outcomes of the MinIO upgrades of a tenant
+const (
+	upgradeCompleted  = "completed"
+	upgradeFailed     = "failed"
+	upgradeRolledBack = "rolled_back"
+)
+
+var (
+	// metricsRegistry holds the metrics served by the Operator on /metrics
+	metricsRegistry = prometheus.NewRegistry()
+
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "depth",
+		Help:      "Current depth of the workqueue",
+	}, []string{"name"})
+	workqueueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "adds_total",
+		Help:      "Total number of items added to the workqueue",
+	}, []string{"name"})
+	workqueueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "queue_duration_seconds",
+		Help:      "How long in seconds an item stays in the workqueue before being processed",
+		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 10),
+	}, []string{"name"})
+	workqueueWorkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "work_duration_seconds",
+		Help:      "How long in seconds processing an item from the workqueue takes",
+		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 10),
+	}, []string{"name"})
+	workqueueUnfinishedWork = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "unfinished_work_seconds",
+		Help:      "How many seconds of work is in progress and hasn't been observed by work_duration yet",
+	}, []string{"name"})
+	workqueueLongestRunningProcessor = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "longest_running_processor_seconds",
+		Help:      "How many seconds the longest running processor of the workqueue has been running",
+	}, []string{"name"})
+	workqueueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "retries_total",
+		Help:      "Total number of retries handled by the workqueue",
+	}, []string{"name"})
+
+	syncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_sync_duration_seconds",
+		Help:      "How long in seconds the reconciliation of a tenant takes",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 120, 300},
+	}, []string{"namespace", "tenant"})
+	syncErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_sync_errors_total",
+		Help:      "Total number of failed reconciliations of a tenant",
+	}, []string{"namespace", "tenant"})
+	csrFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_csr_failures_total",
+		Help:      "Total number of certificate signing requests that failed to be issued for a tenant",
+	}, []string{"namespace", "tenant", "certificate"})
+	upgrades = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_upgrades_total",
+		Help:      "Total number of MinIO upgrades of a tenant by outcome",
+	}, []string{"namespace", "tenant", "outcome"})
+
+	drivesOnline = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_drives_online",
+		Help:      "Number of online drives of a tenant",
+	}, []string{"namespace", "tenant"})
+	drivesOffline = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_drives_offline",
+		Help:      "Number of offline drives of a tenant",
+	}, []string{"namespace", "tenant"})
+	drivesHealing = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_drives_healing",
+		Help:      "Number of healing drives of a tenant",
+	}, []string{"namespace", "tenant"})
+	writeQuorum = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_write_quorum",
+		Help:      "Number of drives a tenant needs online to accept writes",
+	}, []string{"namespace", "tenant"})
+	healthStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "tenant_health_status",
+		Help:      "Health status of a tenant, the current status is set to 1",
+	}, []string{"namespace", "tenant", "status"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		workqueueDepth,
+		workqueueAdds,
+		workqueueLatency,
+		workqueueWorkDuration,
+		workqueueUnfinishedWork,
+		workqueueLongestRunningProcessor,
+		workqueueRetries,
+		syncDuration,
+		syncErrors,
+		csrFailures,
+		upgrades,
+		drivesOnline,
+		drivesOffline,
+		drivesHealing,
+		writeQuorum,
+		healthStatus,
+	)
+	// the provider must be set before the workqueue of the controller is created
+	queue.SetProvider(workqueueMetricsProvider{})
+}
+
+// metricsHandler serves the metrics of the Operator in the Prometheus exposition format
+func metricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+}
+
+// workqueueMetricsProvider reports the metrics of the controller workqueue
+type workqueueMetricsProvider struct{}
+
+func (workqueueMetricsProvider) NewDepthMetric(name string) queue.GaugeMetric {
+	return workqueueDepth.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewAddsMetric(name string) queue.CounterMetric {
+	return workqueueAdds.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLatencyMetric(name string) queue.HistogramMetric {
+	return workqueueLatency.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewWorkDurationMetric(name string) queue.HistogramMetric {
+	return workqueueWorkDuration.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) queue.SettableGaugeMetric {
+	return workqueueUnfinishedWork.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) queue.SettableGaugeMetric {
+	return workqueueLongestRunningProcessor.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewRetriesMetric(name string) queue.CounterMetric {
+	return workqueueRetries.WithLabelValues(name)
+}
+
+// observeSync records the duration and the outcome of the reconciliation of a tenant
+func observeSync(namespace, name string, start time.Time, err error) {
+	syncDuration.WithLabelValues(namespace, name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		syncErrors.WithLabelValues(namespace, name).Inc()
+	}
+}
+
+// observeCSRFailure counts a certificate that couldn't be issued for the tenant
+func observeCSRFailure(tenant *miniov2.Tenant, certificate string) {
+	csrFailures.WithLabelValues(tenant.Namespace, tenant.Name, certificate).Inc()
+}
+
+// observeUpgrade counts the outcome of a MinIO upgrade of the tenant
+func observeUpgrade(tenant *miniov2.Tenant, outcome string) {
+	upgrades.WithLabelValues(tenant.Namespace, tenant.Name, outcome).Inc()
+}
+
+// observeHealth exports the health of the tenant found by the health monitor
+func observeHealth(tenant *miniov2.Tenant) {
+	drivesOnline.WithLabelValues(tenant.Namespace, tenant.Name).Set(float64(tenant.Status.DrivesOnline))
+	drivesOffline.WithLabelValues(tenant.Namespace, tenant.Name).Set(float64(tenant.Status.DrivesOffline))
+	drivesHealing.WithLabelValues(tenant.Namespace, tenant.Name).Set(float64(tenant.Status.DrivesHealing))
+	writeQuorum.WithLabelValues(tenant.Namespace, tenant.Name).Set(float64(tenant.Status.WriteQuorum))
+	for _, status := range []miniov2.HealthStatus{miniov2.HealthStatusGreen, miniov2.HealthStatusYellow, miniov2.HealthStatusRed} {
+		value := 0.0
+		if tenant.Status.HealthStatus == status {
+			value = 1
+		}
+		healthStatus.WithLabelValues(tenant.Namespace, tenant.Name, string(status)).Set(value)
+	}
+}
+
+// deleteTenantMetrics drops the metrics of a tenant that no longer exists
+func deleteTenantMetrics(namespace, name string) {
+	for _, vec := range []interface {
+		DeleteLabelValues(...string) bool
+	}{syncDuration, syncErrors, drivesOnline, drivesOffline, drivesHealing, writeQuorum} {
+		vec.DeleteLabelValues(namespace, name)
+	}
+	for _, certificate := range []string{certificateMinIO, certificateMinIOClient, certificateConsole, certificateKES} {
+		csrFailures.DeleteLabelValues(namespace, name, certificate)
+	}
+	for _, outcome := range []string{upgradeCompleted, upgradeFailed, upgradeRolledBack} {
+		upgrades.DeleteLabelValues(namespace, name, outcome)
+	}
+	for _, status := range []miniov2.HealthStatus{miniov2.HealthStatusGreen, miniov2.HealthStatusYellow, miniov2.HealthStatusRed} {
+		healthStatus.DeleteLabelValues(namespace, name, string(status))
+	}
+}
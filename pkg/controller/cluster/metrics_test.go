@@ -0,0 +1,71 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	listers "github.com/minio/operator/pkg/client/listers/minio.min.io/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	queue "k8s.io/client-go/util/workqueue"
+)
+
+func Test_processNextWorkItemDeletedTenant(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	c := &Controller{
+		tenantsLister: listers.NewTenantLister(indexer),
+		workqueue:     queue.NewRateLimitingQueue(MinIOControllerRateLimiter()),
+	}
+	defer c.workqueue.ShutDown()
+
+	// the tenant was synced and checked before it was deleted
+	tenant := &miniov2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "deleted", Namespace: "ns"}}
+	observeSync("ns", "deleted", time.Now(), errors.New("sync failed"))
+	observeHealth(tenant)
+	durations := testutil.CollectAndCount(syncDuration)
+	failures := testutil.CollectAndCount(syncErrors)
+	online := testutil.CollectAndCount(drivesOnline)
+
+	// the informer missed the deletion and only got the tombstone of the tenant
+	c.forgetTenant(cache.DeletedFinalStateUnknown{Key: "ns/deleted", Obj: tenant})
+	if actual := testutil.CollectAndCount(syncDuration); actual != durations-1 {
+		t.Errorf("forgetTenant() left %d sync duration series, expected %d", actual, durations-1)
+	}
+	if actual := testutil.CollectAndCount(syncErrors); actual != failures-1 {
+		t.Errorf("forgetTenant() left %d sync error series, expected %d", actual, failures-1)
+	}
+	if actual := testutil.CollectAndCount(drivesOnline); actual != online-1 {
+		t.Errorf("forgetTenant() left %d drives online series, expected %d", actual, online-1)
+	}
+
+	// the sync that released the finalizer still finds the tenant in the lister
+	deletedAt := metav1.Now()
+	tenant.DeletionTimestamp = &deletedAt
+	if err := indexer.Add(tenant); err != nil {
+		t.Fatal(err)
+	}
+	c.workqueue.Add("ns/deleted")
+	c.processNextWorkItem()
+	if actual := testutil.CollectAndCount(syncDuration); actual != durations-1 {
+		t.Errorf("processNextWorkItem() left %d sync duration series, expected %d", actual, durations-1)
+	}
+}
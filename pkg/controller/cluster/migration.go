@@ -0,0 +1,165 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strconv"
+	"strings"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Event reasons reported while migrating pools to a new number of servers
+const (
+	PoolMigrationStarted = "PoolMigrationStarted"
+	PoolMigrated         = "PoolMigrated"
+)
+
+// startPoolMigration handles a change on the number of servers of a deployed pool. As the servers of a MinIO pool
+// can't change, a new StatefulSet is deployed for the pool with the new number of servers, and the StatefulSet the
+// pool is migrated from is decommissioned once the new one is initialized. Only one pool is migrated at a time. It
+// returns true if the migration started.
+func (c *Controller) startPoolMigration(ctx context.Context, tenant *miniov2.Tenant, pool *miniov2.Pool, ss *appsv1.StatefulSet, rootCAs *x509.CertPool) (*miniov2.Tenant, bool, error) {
+	for _, poolStatus := range tenant.Status.Pools {
+		if poolStatus.MigratingTo != "" {
+			klog.Infof("Pool %s of Tenant '%s/%s' will be migrated to %d servers once StatefulSet %s is migrated",
+				pool.Name, tenant.Namespace, tenant.Name, pool.Servers, poolStatus.SSName)
+			tenant, err := c.updateTenantStatus(ctx, tenant, fmt.Sprintf("Waiting to migrate pool %s to %d servers", pool.Name, pool.Servers), 0)
+			return tenant, false, err
+		}
+	}
+	pi := tenant.PoolStatusIndex(pool)
+	if pi < 0 {
+		return tenant, false, nil
+	}
+	// the data of the pool moves to the new StatefulSet, MinIO must be able to take it
+	tenant, err := c.checkMinIOHealth(ctx, tenant, rootCAs)
+	if err != nil {
+		return tenant, false, err
+	}
+
+	volumes := int32(len(ss.Spec.VolumeClaimTemplates))
+	if tenant.Spec.SideCars != nil {
+		volumes -= int32(len(tenant.Spec.SideCars.VolumeClaimTemplates))
+	}
+	ssName, err := c.migratedPoolStatefulsetName(ctx, tenant, pool, ss.Name)
+	if err != nil {
+		return tenant, false, err
+	}
+	poolStatus := &tenant.Status.Pools[pi]
+	poolStatus.Name = pool.Name
+	poolStatus.Servers = *ss.Spec.Replicas
+	poolStatus.VolumesPerServer = volumes
+	poolStatus.MigratingTo = ssName
+	tenant.Status.Pools = append(tenant.Status.Pools, miniov2.PoolStatus{
+		SSName: ssName,
+		State:  miniov2.PoolNotCreated,
+	})
+
+	msg := fmt.Sprintf("Migrating pool %s from %d to %d servers, StatefulSet %s is replaced by %s", pool.Name, *ss.Spec.Replicas, pool.Servers, ss.Name, ssName)
+	klog.Infof("Tenant '%s/%s': %s", tenant.Namespace, tenant.Name, msg)
+	c.recorder.Event(tenant, corev1.EventTypeNormal, PoolMigrationStarted, msg)
+	tenant, err = c.updatePoolStatus(ctx, tenant)
+	return tenant, err == nil, err
+}
+
+// migratedPoolStatefulsetName returns the name of the StatefulSet the pool is migrated to, skipping the names still
+// owning PVCs, like the ones retained from a pool that was removed and added back, so they are never bound again
+func (c *Controller) migratedPoolStatefulsetName(ctx context.Context, tenant *miniov2.Tenant, pool *miniov2.Pool, from string) (string, error) {
+	pvcs, err := c.listPVCs(ctx, tenant, fmt.Sprintf("%s=%s", miniov2.TenantLabel, tenant.Name))
+	if err != nil {
+		return "", err
+	}
+	ssName := tenant.MigratedPoolStatefulsetName(pool, from)
+	for len(statefulSetPVCs(pvcs, ssName)) > 0 {
+		ssName = tenant.MigratedPoolStatefulsetName(pool, ssName)
+	}
+	return ssName, nil
+}
+
+// migratePools decommissions the StatefulSets pools are migrated from, once the StatefulSet they are migrated to is
+// initialized
+func (c *Controller) migratePools(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	changed := false
+	for i := range tenant.Status.Pools {
+		poolStatus := &tenant.Status.Pools[i]
+		if poolStatus.MigratingTo == "" || poolStatus.State == miniov2.PoolDecommissioning || poolStatus.State == miniov2.PoolDecommissioned {
+			continue
+		}
+		initialized := false
+		for _, target := range tenant.Status.Pools {
+			if target.SSName == poolStatus.MigratingTo {
+				initialized = target.State == miniov2.PoolInitialized
+				break
+			}
+		}
+		if !initialized {
+			klog.Infof("Waiting for StatefulSet %s to be initialized to decommission the pool of StatefulSet %s", poolStatus.MigratingTo, poolStatus.SSName)
+			continue
+		}
+		klog.Infof("StatefulSet %s is initialized, decommissioning the pool of StatefulSet %s", poolStatus.MigratingTo, poolStatus.SSName)
+		poolStatus.State = miniov2.PoolDecommissioning
+		changed = true
+	}
+	if !changed {
+		return tenant, nil
+	}
+	return c.updatePoolStatus(ctx, tenant)
+}
+
+// isStatefulSetPVC returns true if the PVC was created from a volume claim template of the StatefulSet, PVCs are named
+// `<volume claim template>-<statefulset>-<ordinal>`
+func isStatefulSetPVC(pvcName, ssName string) bool {
+	i := strings.LastIndex(pvcName, "-")
+	if i < 0 {
+		return false
+	}
+	if _, err := strconv.ParseUint(pvcName[i+1:], 10, 32); err != nil {
+		return false
+	}
+	return strings.HasSuffix(pvcName[:i], "-"+ssName)
+}
+
+// statefulSetPVCs filters the PVCs created from the volume claim templates of the StatefulSet, pools migrated to a
+// new StatefulSet share their labels with the StatefulSet they are migrated from
+func statefulSetPVCs(pvcs []corev1.PersistentVolumeClaim, ssName string) []corev1.PersistentVolumeClaim {
+	var ssPVCs []corev1.PersistentVolumeClaim
+	for _, pvc := range pvcs {
+		if isStatefulSetPVC(pvc.Name, ssName) {
+			ssPVCs = append(ssPVCs, pvc)
+		}
+	}
+	return ssPVCs
+}
+
+// statefulSetPods filters the pods controlled by the StatefulSet
+func statefulSetPods(pods []corev1.Pod, ssName string) []corev1.Pod {
+	var ssPods []corev1.Pod
+	for _, pod := range pods {
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "StatefulSet" && owner.Name == ssName {
+			ssPods = append(ssPods, pod)
+		}
+	}
+	return ssPods
+}
@@ -0,0 +1,84 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_migratedPoolStatefulsetName(t *testing.T) {
+	pvc := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels:    map[string]string{miniov2.TenantLabel: "tenant", miniov2.PoolLabel: "pool-0"},
+		}}
+	}
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Spec:       miniov2.TenantSpec{Pools: []miniov2.Pool{{Name: "pool-0", Servers: 4, VolumesPerServer: 1}}},
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		pvcs     []*corev1.PersistentVolumeClaim
+		expected string
+	}{
+		{
+			name:     "First migration",
+			from:     "tenant-pool-0",
+			expected: "tenant-pool-0-m1",
+		},
+		{
+			name:     "Migrated pool",
+			from:     "tenant-pool-0-m1",
+			pvcs:     []*corev1.PersistentVolumeClaim{pvc("data0-tenant-pool-0-0"), pvc("data0-tenant-pool-0-m1-0")},
+			expected: "tenant-pool-0-m2",
+		},
+		{
+			name:     "Retained volumes",
+			from:     "tenant-pool-0",
+			pvcs:     []*corev1.PersistentVolumeClaim{pvc("data0-tenant-pool-0-0"), pvc("data0-tenant-pool-0-m1-0"), pvc("data0-tenant-pool-0-m2-3")},
+			expected: "tenant-pool-0-m3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			for _, claim := range tt.pvcs {
+				if err := kubeClient.Tracker().Add(claim); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := &Controller{kubeClientSet: kubeClient}
+			actual, err := c.migratedPoolStatefulsetName(context.Background(), tenant, &tenant.Spec.Pools[0], tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tt.expected {
+				t.Errorf("migratedPoolStatefulsetName() = %s, expected %s", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -30,7 +30,6 @@ import (
 
 	"github.com/minio/operator/pkg/resources/services"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -61,7 +60,14 @@ func (c *Controller) checkAndCreateMinIOCSR(ctx context.Context, nsName types.Na
 func (c *Controller) checkMinIOSCertificatesStatus(ctx context.Context, tenant *miniov2.Tenant, nsName types.NamespacedName) error {
 	if tenant.AutoCert() {
 		// check if there's already a TLS secret for MinIO
-		_, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, tenant.MinIOTLSSecretName(), metav1.GetOptions{})
+		secret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, tenant.MinIOTLSSecretName(), metav1.GetOptions{})
+		// the ingress hostnames may have changed since the certificate was issued
+		if hosts := tenant.MinIOIngressHosts(); err == nil && !certificateCoversHosts(secret, hosts) {
+			if err = c.deleteCertificate(ctx, tenant, secret.Name, tenant.MinIOCSRName(), hosts); err != nil {
+				return err
+			}
+			return c.checkAndCreateMinIOCSR(ctx, nsName, tenant)
+		}
 		if err != nil {
 			if k8serrors.IsNotFound(err) {
 				if err := c.checkAndCreateMinIOCSR(ctx, nsName, tenant); err != nil {
@@ -86,6 +92,33 @@ func (c *Controller) checkMinIOSCertificatesStatus(ctx context.Context, tenant *
 	return nil
 }
 
+// consolidateMinIOServiceName records the name of the MinIO service in the tenant status. Tenants deployed when only
+// one Tenant was allowed per namespace own a service named `minio` and keep it, any other tenant gets a service named
+// after itself so several tenants can coexist in the same namespace.
+func (c *Controller) consolidateMinIOServiceName(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	if tenant.Status.MinIOServiceName != "" {
+		return tenant, nil
+	}
+	serviceName, err := c.resolveMinIOServiceName(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return c.updateMinIOServiceNameStatus(ctx, tenant, serviceName)
+}
+
+// resolveMinIOServiceName returns the name of the MinIO service of a tenant that has none recorded yet, the legacy
+// `minio` service if the tenant owns it
+func (c *Controller) resolveMinIOServiceName(tenant *miniov2.Tenant) (string, error) {
+	svc, err := c.serviceLister.Services(tenant.Namespace).Get(miniov2.LegacyMinIOCIServiceName)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && metav1.IsControlledBy(svc, tenant) {
+		return miniov2.LegacyMinIOCIServiceName, nil
+	}
+	return tenant.Name, nil
+}
+
 // checkMinIOSvc validates the existence of the MinIO service and validate it's status against what the specification
 // states
 func (c *Controller) checkMinIOSvc(ctx context.Context, tenant *miniov2.Tenant, nsName types.NamespacedName) error {
@@ -98,39 +131,14 @@ func (c *Controller) checkMinIOSvc(ctx context.Context, tenant *miniov2.Tenant,
 			}
 			klog.V(2).Infof("Creating a new Cluster IP Service for cluster %q", nsName)
 			// Create the clusterIP service for the Tenant
-			svc = services.NewClusterIPForMinIO(tenant)
-			svc, err = c.kubeClientSet.CoreV1().Services(tenant.Namespace).Create(ctx, svc, metav1.CreateOptions{})
-			if err != nil {
-				return err
-			}
-		} else {
+			_, err = c.createService(ctx, tenant, services.NewClusterIPForMinIO(tenant))
 			return err
 		}
+		return err
 	}
 
-	// check the expose status of the MinIO ClusterIP service
-	minioSvcMatchesSpec := true
-	// compare any other change from what is specified on the tenant
-	expectedSvc := services.NewClusterIPForMinIO(tenant)
-	if !equality.Semantic.DeepDerivative(expectedSvc.Spec, svc.Spec) {
-		// some field set by the operator has changed
-		minioSvcMatchesSpec = false
-	}
-
-	// check the specification of the MinIO ClusterIP service
-	if !minioSvcMatchesSpec {
-		svc.ObjectMeta.Annotations = expectedSvc.ObjectMeta.Annotations
-		svc.ObjectMeta.Labels = expectedSvc.ObjectMeta.Labels
-		svc.Spec.Ports = expectedSvc.Spec.Ports
-		// we can only expose the service, not un-expose it
-		if tenant.Spec.ExposeServices != nil && tenant.Spec.ExposeServices.MinIO && svc.Spec.Type != v1.ServiceTypeLoadBalancer {
-			svc.Spec.Type = v1.ServiceTypeLoadBalancer
-		}
-		_, err = c.kubeClientSet.CoreV1().Services(tenant.Namespace).Update(ctx, svc, metav1.UpdateOptions{})
-		if err != nil {
-			return err
-		}
-	}
+	// push any change from what is specified on the tenant
+	_, err = c.syncService(ctx, tenant, svc, services.NewClusterIPForMinIO(tenant))
 	return err
 }
 
@@ -164,6 +172,8 @@ func generateMinIOCryptoData(tenant *miniov2.Tenant, hostsTemplate string) ([]by
 		dnsNames = append(tenant.Spec.CertConfig.DNSNames, hosts...)
 	}
 	dnsNames = append(dnsNames, tenant.MinIOBucketBaseWildcardDomain())
+	// the hostnames routed from outside of the cluster, so the ingress can verify the MinIO certificate
+	dnsNames = append(dnsNames, tenant.MinIOIngressHosts()...)
 
 	for _, dnsName := range dnsNames {
 		csrExtensions = append(csrExtensions, pkix.Extension{
@@ -211,6 +221,8 @@ func (c *Controller) createMinIOCSR(ctx context.Context, tenant *miniov2.Tenant)
 	certbytes, err := c.fetchCertificate(ctx, tenant.MinIOCSRName())
 	if err != nil {
 		klog.Errorf("Unexpected error during the creation of the csr/%s: %v", tenant.MinIOCSRName(), err)
+		c.recorder.Event(tenant, v1.EventTypeWarning, CertificateFailed, fmt.Sprintf("Unable to get the certificate of csr/%s: %v", tenant.MinIOCSRName(), err))
+		observeCSRFailure(tenant, certificateMinIO)
 		return err
 	}
 
@@ -246,6 +258,8 @@ func (c *Controller) createMinIOClientCSR(ctx context.Context, tenant *miniov2.T
 	certbytes, err := c.fetchCertificate(ctx, tenant.MinIOClientCSRName())
 	if err != nil {
 		klog.Errorf("Unexpected error during the creation of the csr/%s: %v", tenant.MinIOClientCSRName(), err)
+		c.recorder.Event(tenant, v1.EventTypeWarning, CertificateFailed, fmt.Sprintf("Unable to get the certificate of csr/%s: %v", tenant.MinIOClientCSRName(), err))
+		observeCSRFailure(tenant, certificateMinIOClient)
 		return err
 	}
 
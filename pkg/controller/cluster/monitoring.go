@@ -20,15 +20,22 @@ package cluster
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"log"
+	"math/rand"
 	"net"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/labels"
@@ -36,116 +43,339 @@ import (
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 )
 
-// recurrentTenantStatusMonitor loop that checks every 3 minutes for tenants health
+const (
+	// tenantHealthCheckTimeout bounds the time spent checking the health of a single tenant
+	tenantHealthCheckTimeout = 2 * time.Minute
+	// healthCheckMaxBackoff is the longest a tenant that keeps failing its health checks goes unchecked
+	healthCheckMaxBackoff = time.Hour
+	// healthCheckJitterFactor delays every monitoring pass by up to this fraction of the monitoring interval
+	healthCheckJitterFactor = 0.1
+	// healthCheckSpread is the longest a check waits to start, so the tenants aren't all queried at the same time
+	healthCheckSpread = 2 * time.Second
+)
+
+// recurrentTenantStatusMonitor checks the health of the tenants every monitoring interval, 3 minutes by default
 func (c *Controller) recurrentTenantStatusMonitor(stopCh <-chan struct{}) {
-	// do an initial check, then start the periodic check
-	if err := c.tenantsHealthMonitor(); err != nil {
-		log.Println(err)
-	}
-	// How often will this function run
-	interval := miniov2.GetMonitoringInterval()
-	ticker := time.NewTicker(time.Duration(interval) * time.Minute)
-	defer func() {
-		log.Println("recurrent pod status monitor closed")
-	}()
-	for {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
 		select {
-		case <-ticker.C:
-			if err := c.tenantsHealthMonitor(); err != nil {
-				log.Println(err)
-			}
 		case <-stopCh:
-			ticker.Stop()
-			return
+			cancel()
+		case <-ctx.Done():
 		}
-	}
-
+	}()
+	interval := time.Duration(miniov2.GetMonitoringInterval()) * time.Minute
+	// the first pass runs right away, the next ones a jittered interval after the previous pass finished, so a slow
+	// pass never overlaps with the next one
+	wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.tenantsHealthMonitor(ctx, interval); err != nil {
+			log.Println(err)
+		}
+	}, interval, healthCheckJitterFactor, true)
+	log.Println("recurrent pod status monitor closed")
 }
 
-func (c *Controller) tenantsHealthMonitor() error {
+// tenantsHealthMonitor checks the health of all the tenants with a bounded pool of workers, the tenants that keep
+// failing their health checks are skipped until their backoff expires
+func (c *Controller) tenantsHealthMonitor(ctx context.Context, interval time.Duration) error {
 	// list all tenants and get their cluster health
 	tenants, err := c.tenantsLister.Tenants("").List(labels.NewSelector())
 	if err != nil {
 		return err
 	}
-	for _, tenant := range tenants {
-		// don't get the tenant cluster health if it doesn't have at least 1 pool initialized
-		oneInitialized := false
-		for _, pool := range tenant.Status.Pools {
-			if pool.State == miniov2.PoolInitialized {
-				oneInitialized = true
+
+	queue := make(chan *miniov2.Tenant)
+	var wg sync.WaitGroup
+	for i := 0; i < miniov2.GetMonitoringWorkers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tenant := range queue {
+				select {
+				case <-time.After(time.Duration(rand.Int63n(int64(healthCheckSpread)))):
+				case <-ctx.Done():
+					continue
+				}
+				// NEVER modify objects from the store
+				c.checkTenantHealth(ctx, tenant.DeepCopy())
 			}
-		}
-		if !oneInitialized {
+		}()
+	}
+
+	now := time.Now()
+dispatch:
+	for _, tenant := range tenants {
+		if !healthCheckDue(tenant, interval, now) {
 			continue
 		}
+		select {
+		case queue <- tenant:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+	return nil
+}
 
-		// get cluster health for tenant
-		healthResult, err := getMinIOHealthStatus(tenant, RegularMode)
-		if err != nil {
-			// show the error and continue
-			klog.V(2).Infof(err.Error())
-			continue
+// healthCheckDue returns true if the tenant has at least 1 pool initialized and isn't backing off from failed health
+// checks
+func healthCheckDue(tenant *miniov2.Tenant, interval time.Duration, now time.Time) bool {
+	oneInitialized := false
+	for _, pool := range tenant.Status.Pools {
+		if pool.State == miniov2.PoolInitialized {
+			oneInitialized = true
 		}
+	}
+	if !oneInitialized {
+		return false
+	}
+	if tenant.Status.HealthCheckFailures == 0 || tenant.Status.HealthCheckedAt == nil {
+		return true
+	}
+	return now.Sub(tenant.Status.HealthCheckedAt.Time) >= healthCheckBackoff(tenant.Status.HealthCheckFailures, interval)
+}
 
-		// get mc admin info
-		minioSecretName := tenant.Spec.CredsSecret.Name
-		minioSecret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(context.Background(), minioSecretName, metav1.GetOptions{})
+// healthCheckBackoff returns how long a tenant goes unchecked after failing its last health checks, starting at the
+// monitoring interval and doubling with every failure up to healthCheckMaxBackoff
+func healthCheckBackoff(failures int32, interval time.Duration) time.Duration {
+	backoff := interval
+	for i := int32(1); i < failures && backoff < healthCheckMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > healthCheckMaxBackoff {
+		return healthCheckMaxBackoff
+	}
+	return backoff
+}
+
+// checkTenantHealth checks the health of the tenant within tenantHealthCheckTimeout and records the time and the
+// outcome of the check in the tenant status
+func (c *Controller) checkTenantHealth(ctx context.Context, tenant *miniov2.Tenant) {
+	// a tenant not synced since the MinIO service name is recorded may still use the legacy service
+	if tenant.Status.MinIOServiceName == "" {
+		serviceName, err := c.resolveMinIOServiceName(tenant)
 		if err != nil {
-			// show the error and continue
-			klog.V(2).Infof(err.Error())
-			continue
+			klog.V(2).Infof("Unable to get the MinIO service of Tenant '%s/%s': %v", tenant.Namespace, tenant.Name, err)
+			return
 		}
+		tenant.Status.MinIOServiceName = serviceName
+	}
 
-		adminClnt, err := tenant.NewMinIOAdmin(minioSecret.Data)
-		if err != nil {
-			// show the error and continue
-			klog.V(2).Infof(err.Error())
-			continue
+	checkCtx, cancel := context.WithTimeout(ctx, tenantHealthCheckTimeout)
+	err := c.updateTenantHealth(checkCtx, tenant)
+	cancel()
+
+	checkedAt := metav1.Now()
+	tenant.Status.HealthCheckedAt = &checkedAt
+	switch {
+	case isCertificateVerificationError(err):
+		message := certificateVerificationMessage(err)
+		klog.Warningf("Health check of Tenant '%s/%s' failed: %s", tenant.Namespace, tenant.Name, message)
+		if tenant.Status.HealthCheckFailures == 0 {
+			c.recorder.Event(tenant, corev1.EventTypeWarning, CertificateUntrusted, message)
+		}
+		tenant.Status.HealthCheckError = message
+		tenant.Status.HealthCheckFailures++
+		if !degradedByConfiguration(tenant) {
+			setTenantConditions(tenant, newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, miniov2.ReasonUntrustedCertificate, message))
 		}
+	case err != nil:
+		klog.V(2).Infof("Health check of Tenant '%s/%s' failed: %v", tenant.Namespace, tenant.Name, err)
+		tenant.Status.HealthCheckError = err.Error()
+		tenant.Status.HealthCheckFailures++
+	default:
+		tenant.Status.HealthCheckError = ""
+		tenant.Status.HealthCheckFailures = 0
+	}
 
-		srvInfoCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-		storageInfo, err := adminClnt.StorageInfo(srvInfoCtx)
-		if err != nil {
-			// show the error and continue
-			klog.V(2).Infof(err.Error())
+	if _, err = c.updateHealthStatus(ctx, tenant); err != nil {
+		klog.V(2).Infof(err.Error())
+	}
+}
+
+// setHealthStatus copies the health recorded by the health monitor from checked to tenant, so it survives an update of
+// the tenant status that conflicts with a sync of the tenant
+func setHealthStatus(tenant, checked *miniov2.Tenant) {
+	tenant.Status.HealthStatus = checked.Status.HealthStatus
+	tenant.Status.WriteQuorum = checked.Status.WriteQuorum
+	tenant.Status.DrivesOnline = checked.Status.DrivesOnline
+	tenant.Status.DrivesOffline = checked.Status.DrivesOffline
+	tenant.Status.DrivesHealing = checked.Status.DrivesHealing
+	tenant.Status.ObjectsSize = checked.Status.ObjectsSize
+	tenant.Status.HealthCheckedAt = checked.Status.HealthCheckedAt
+	tenant.Status.HealthCheckError = checked.Status.HealthCheckError
+	tenant.Status.HealthCheckFailures = checked.Status.HealthCheckFailures
+	tenant.Status.OfflineDrives = checked.Status.OfflineDrives
+
+	checkedPools := make(map[string]miniov2.PoolStatus)
+	for _, pool := range checked.Status.Pools {
+		checkedPools[pool.SSName] = pool
+	}
+	for i := range tenant.Status.Pools {
+		pool := &tenant.Status.Pools[i]
+		checkedPool, ok := checkedPools[pool.SSName]
+		if !ok {
 			continue
 		}
+		pool.DrivesOnline = checkedPool.DrivesOnline
+		pool.DrivesOffline = checkedPool.DrivesOffline
+		pool.DrivesHealing = checkedPool.DrivesHealing
+		pool.RawCapacity = checkedPool.RawCapacity
+		pool.UsableCapacity = checkedPool.UsableCapacity
+		pool.UsedBytes = checkedPool.UsedBytes
+		pool.ErasureSets = checkedPool.ErasureSets
+		pool.ErasureSetsDegraded = checkedPool.ErasureSetsDegraded
+		pool.ErasureSetsQuorumLost = checkedPool.ErasureSetsQuorumLost
+		pool.HealthStatus = checkedPool.HealthStatus
+	}
 
-		onlineDisks := 0
-		offlineDisks := 0
-		for _, d := range storageInfo.Disks {
-			if d.State == "ok" {
-				onlineDisks++
-			} else {
-				offlineDisks++
-			}
-		}
+	// configuration problems reported by syncHandler take precedence over the health of the tenant
+	degraded := meta.FindStatusCondition(checked.Status.Conditions, miniov2.TenantConditionDegraded)
+	if degraded != nil && !degradedByConfiguration(tenant) {
+		setTenantConditions(tenant, *degraded)
+	}
+}
+
+// healthStatusOnlyChanged returns true if the only difference between the two versions of the tenant is the health
+// recorded by the health monitor, which doesn't need another sync of the tenant
+func healthStatusOnlyChanged(oldTenant, newTenant *miniov2.Tenant) bool {
+	if oldTenant.Generation != newTenant.Generation ||
+		!equality.Semantic.DeepEqual(oldTenant.Labels, newTenant.Labels) ||
+		!equality.Semantic.DeepEqual(oldTenant.Annotations, newTenant.Annotations) ||
+		!equality.Semantic.DeepEqual(oldTenant.Finalizers, newTenant.Finalizers) ||
+		!equality.Semantic.DeepEqual(oldTenant.DeletionTimestamp, newTenant.DeletionTimestamp) {
+		return false
+	}
+	// a tenant turning green or red may let the sync move on, e.g. to record an upgraded image as known good
+	if oldTenant.Status.HealthStatus != newTenant.Status.HealthStatus {
+		return false
+	}
+	checked := oldTenant.DeepCopy()
+	setHealthStatus(checked, newTenant)
+	return equality.Semantic.DeepEqual(checked.Status, newTenant.Status)
+}
 
-		tenant.Status.DrivesHealing = int32(healthResult.HealingDrives)
-		tenant.Status.WriteQuorum = int32(healthResult.WriteQuorumDrives)
+// updateTenantHealth gets the cluster health and the drives of the tenant from MinIO and updates the health of the
+// tenant status with them
+func (c *Controller) updateTenantHealth(ctx context.Context, tenant *miniov2.Tenant) error {
+	rootCAs, err := c.getTenantRootCAs(ctx, tenant)
+	if err != nil {
+		return err
+	}
 
-		tenant.Status.DrivesOnline = int32(onlineDisks)
-		tenant.Status.DrivesOffline = int32(offlineDisks)
+	// get cluster health for tenant
+	healthResult, err := getMinIOHealthStatus(ctx, tenant, rootCAs, RegularMode)
+	if err != nil {
+		return err
+	}
 
-		tenant.Status.HealthStatus = miniov2.HealthStatusGreen
+	// get mc admin info
+	minioSecretName := tenant.Spec.CredsSecret.Name
+	minioSecret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, minioSecretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
 
-		if tenant.Status.DrivesOffline > 0 || tenant.Status.DrivesHealing > 0 {
-			tenant.Status.HealthStatus = miniov2.HealthStatusYellow
-		}
-		if tenant.Status.DrivesOnline < tenant.Status.WriteQuorum {
-			tenant.Status.HealthStatus = miniov2.HealthStatusRed
-		}
+	adminClnt, err := tenant.NewMinIOAdmin(minioSecret.Data, rootCAs)
+	if err != nil {
+		return err
+	}
 
-		if _, err = c.updatePoolStatus(context.Background(), tenant); err != nil {
-			klog.V(2).Infof(err.Error())
+	storageInfo, err := adminClnt.StorageInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	onlineDisks := 0
+	offlineDisks := 0
+	for _, d := range storageInfo.Disks {
+		if d.State == "ok" {
+			onlineDisks++
+		} else {
+			offlineDisks++
 		}
+	}
+
+	tenant.Status.DrivesHealing = int32(healthResult.HealingDrives)
+	tenant.Status.WriteQuorum = int32(healthResult.WriteQuorumDrives)
+
+	tenant.Status.DrivesOnline = int32(onlineDisks)
+	tenant.Status.DrivesOffline = int32(offlineDisks)
+
+	previousHealth := tenant.Status.HealthStatus
+	tenant.Status.HealthStatus = miniov2.HealthStatusGreen
 
+	if tenant.Status.DrivesOffline > 0 || tenant.Status.DrivesHealing > 0 {
+		tenant.Status.HealthStatus = miniov2.HealthStatusYellow
+	}
+	if tenant.Status.DrivesOnline < tenant.Status.WriteQuorum {
+		tenant.Status.HealthStatus = miniov2.HealthStatusRed
+	}
+
+	if tenant.Status.HealthStatus != previousHealth {
+		c.recordHealthTransition(tenant, previousHealth)
+	}
+	// the tenant may have been deleted while it was checked, its metrics are already gone
+	if !c.tenantDeleted(tenant.Namespace, tenant.Name) {
+		observeHealth(tenant)
+	}
+
+	setPoolsHealth(tenant, storageInfo)
+	// the data usage is only refreshed by the MinIO scanner, it's not part of the health of the tenant
+	if usage, err := adminClnt.DataUsageInfo(ctx); err != nil {
+		klog.V(2).Infof("Unable to get the data usage of Tenant '%s/%s': %v", tenant.Namespace, tenant.Name, err)
+	} else {
+		tenant.Status.ObjectsSize = int64(usage.ObjectsTotalSize)
+	}
+
+	// track the offline drives, replacing them if the tenant asks for it
+	if err = c.checkDrives(ctx, tenant, storageInfo); err != nil {
+		klog.V(2).Infof(err.Error())
+	}
+
+	// configuration problems reported by syncHandler take precedence over the health of the drives
+	if !degradedByConfiguration(tenant) {
+		setTenantConditions(tenant, healthDegradedCondition(tenant))
 	}
 	return nil
 }
 
+// recordHealthTransition reports a change on the health status of the tenant as an event, degraded health is reported
+// as a warning
+func (c *Controller) recordHealthTransition(tenant *miniov2.Tenant, previous miniov2.HealthStatus) {
+	eventType := corev1.EventTypeWarning
+	if tenant.Status.HealthStatus == miniov2.HealthStatusGreen {
+		// a tenant found healthy on the first check is not worth an event
+		if previous == "" {
+			return
+		}
+		eventType = corev1.EventTypeNormal
+	}
+	from := string(previous)
+	if from == "" {
+		from = "unknown"
+	}
+	c.recorder.Event(tenant, eventType, HealthStatusChanged, fmt.Sprintf("Health changed from %s to %s: %d drives online, %d drives offline, %d drives healing, write quorum %d",
+		from, tenant.Status.HealthStatus, tenant.Status.DrivesOnline, tenant.Status.DrivesOffline, tenant.Status.DrivesHealing, tenant.Status.WriteQuorum))
+}
+
+// healthDegradedCondition returns the Degraded condition matching the health status of the tenant
+func healthDegradedCondition(tenant *miniov2.Tenant) metav1.Condition {
+	switch tenant.Status.HealthStatus {
+	case miniov2.HealthStatusRed:
+		return newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, miniov2.ReasonQuorumLost,
+			fmt.Sprintf("%d drives online, write quorum requires %d", tenant.Status.DrivesOnline, tenant.Status.WriteQuorum))
+	case miniov2.HealthStatusYellow:
+		return newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, miniov2.ReasonReducedResilience,
+			fmt.Sprintf("%d drives offline, %d drives healing", tenant.Status.DrivesOffline, tenant.Status.DrivesHealing))
+	default:
+		return newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionFalse, miniov2.ReasonHealthy, "All drives are online")
+	}
+}
+
 // HealthResult holds the results from cluster/health query into MinIO
 type HealthResult struct {
 	StatusCode        int
@@ -163,14 +393,15 @@ const (
 	RegularMode = "RegularMode"
 )
 
-func getHealthCheckTransport() *http.Transport {
+// getHealthCheckTransport returns the transport to reach a tenant, verifying its certificate against rootCAs
+func getHealthCheckTransport(rootCAs *x509.CertPool) *http.Transport {
 	// Keep TLS config.
 	tlsConfig := &tls.Config{
 		// Can't use SSLv3 because of POODLE and BEAST
 		// Can't use TLSv1.0 because of POODLE and BEAST using CBC cipher
 		// Can't use TLSv1.1 because of RC4 cipher usage
-		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: true, // FIXME: use trusted CA
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    rootCAs,
 	}
 	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
@@ -192,30 +423,38 @@ func getHealthCheckTransport() *http.Transport {
 // getMinIOHealthStatus returns the cluster health for a Tenant.
 // There's two types of questions we can make to MinIO's cluster/health one asking if the cluster is healthy `RegularMode`
 // or if it's acceptable to remove a node `MaintenanceMode`
-func getMinIOHealthStatus(tenant *miniov2.Tenant, mode HealthMode) (*HealthResult, error) {
-	return getMinIOHealthStatusWithRetry(tenant, mode, 5)
+func getMinIOHealthStatus(ctx context.Context, tenant *miniov2.Tenant, rootCAs *x509.CertPool, mode HealthMode) (*HealthResult, error) {
+	return getMinIOHealthStatusWithRetry(ctx, tenant.GetTenantServiceURL(), rootCAs, mode, 5)
 }
 
-// getMinIOHealthStatusWithRetry returns the cluster health for a Tenant.
-// There's two types of questions we can make to MinIO's cluster/health one asking if the cluster is healthy `RegularMode`
-// or if it's acceptable to remove a node `MaintenanceMode`
-func getMinIOHealthStatusWithRetry(tenant *miniov2.Tenant, mode HealthMode, tryCount int) (*HealthResult, error) {
-	// build the endpoint to contact the Tenant
-	svcURL := tenant.GetTenantServiceURL()
+// getMinIOPodHealthStatus returns the cluster health as seen by one MinIO pod of the Tenant. In `MaintenanceMode` the
+// pod answers whether the cluster keeps its write quorum without it.
+func getMinIOPodHealthStatus(ctx context.Context, tenant *miniov2.Tenant, podName string, rootCAs *x509.CertPool, mode HealthMode) (*HealthResult, error) {
+	scheme := "http"
+	if tenant.TLS() {
+		scheme = "https"
+	}
+	podURL := fmt.Sprintf("%s://%s:%d", scheme, tenant.MinIOHLPodHostname(podName), miniov2.MinIOPort)
+	return getMinIOHealthStatusWithRetry(ctx, podURL, rootCAs, mode, 5)
+}
 
+// getMinIOHealthStatusWithRetry returns the cluster health reported by MinIO at svcURL.
+// There's two types of questions we can make to MinIO's cluster/health one asking if the cluster is healthy `RegularMode`
+// or if it's acceptable to remove a node `MaintenanceMode`. Timeouts are retried until tryCount or ctx runs out.
+func getMinIOHealthStatusWithRetry(ctx context.Context, svcURL string, rootCAs *x509.CertPool, mode HealthMode, tryCount int) (*HealthResult, error) {
 	endpoint := fmt.Sprintf("%s%s", svcURL, "/minio/health/cluster")
 	if mode == MaintenanceMode {
 		endpoint = fmt.Sprintf("%s?maintenance=true", endpoint)
 	}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		log.Println("error request pinging", err)
 		return nil, err
 	}
 
 	httpClient := &http.Client{
-		Transport: getHealthCheckTransport(),
+		Transport: getHealthCheckTransport(rootCAs),
 	}
 	defer httpClient.CloseIdleConnections()
 
@@ -224,12 +463,17 @@ func getMinIOHealthStatusWithRetry(tenant *miniov2.Tenant, mode HealthMode, tryC
 		// if we fail due to timeout, retry
 		if err, ok := err.(net.Error); ok && err.Timeout() && tryCount > 0 {
 			log.Printf("health check failed, retrying %d, err: %s", tryCount, err)
-			time.Sleep(10 * time.Second)
-			return getMinIOHealthStatusWithRetry(tenant, mode, tryCount-1)
+			select {
+			case <-time.After(10 * time.Second):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return getMinIOHealthStatusWithRetry(ctx, svcURL, rootCAs, mode, tryCount-1)
 		}
 		log.Println("error pinging", err)
 		return nil, err
 	}
+	defer resp.Body.Close()
 	driveskHealing := 0
 	if resp.Header.Get("X-Minio-Healing-Drives") != "" {
 		val, err := strconv.Atoi(resp.Header.Get("X-Minio-Healing-Drives"))
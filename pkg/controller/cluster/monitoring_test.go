@@ -0,0 +1,272 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	fakeminio "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_healthCheckDue(t *testing.T) {
+	interval := 3 * time.Minute
+	now := time.Now()
+	checkedAt := func(ago time.Duration) *metav1.Time {
+		at := metav1.NewTime(now.Add(-ago))
+		return &at
+	}
+
+	tests := []struct {
+		name     string
+		status   miniov2.TenantStatus
+		expected bool
+	}{
+		{
+			name:     "No pool initialized",
+			status:   miniov2.TenantStatus{Pools: []miniov2.PoolStatus{{SSName: "tenant-pool-0", State: miniov2.PoolCreated}}},
+			expected: false,
+		},
+		{
+			name:     "Never checked",
+			status:   miniov2.TenantStatus{Pools: []miniov2.PoolStatus{{SSName: "tenant-pool-0", State: miniov2.PoolInitialized}}},
+			expected: true,
+		},
+		{
+			name: "Last check succeeded",
+			status: miniov2.TenantStatus{
+				Pools:           []miniov2.PoolStatus{{SSName: "tenant-pool-0", State: miniov2.PoolInitialized}},
+				HealthCheckedAt: checkedAt(time.Minute),
+			},
+			expected: true,
+		},
+		{
+			name: "Failed once, one interval ago",
+			status: miniov2.TenantStatus{
+				Pools:               []miniov2.PoolStatus{{SSName: "tenant-pool-0", State: miniov2.PoolInitialized}},
+				HealthCheckedAt:     checkedAt(interval),
+				HealthCheckFailures: 1,
+			},
+			expected: true,
+		},
+		{
+			name: "Failed twice, one interval ago",
+			status: miniov2.TenantStatus{
+				Pools:               []miniov2.PoolStatus{{SSName: "tenant-pool-0", State: miniov2.PoolInitialized}},
+				HealthCheckedAt:     checkedAt(interval),
+				HealthCheckFailures: 2,
+			},
+			expected: false,
+		},
+		{
+			name: "Keeps failing, backoff capped",
+			status: miniov2.TenantStatus{
+				Pools:               []miniov2.PoolStatus{{SSName: "tenant-pool-0", State: miniov2.PoolInitialized}},
+				HealthCheckedAt:     checkedAt(healthCheckMaxBackoff),
+				HealthCheckFailures: 100,
+			},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &miniov2.Tenant{Status: tt.status}
+			if actual := healthCheckDue(tenant, interval, now); actual != tt.expected {
+				t.Errorf("healthCheckDue() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_healthStatusOnlyChanged(t *testing.T) {
+	checkedAt := metav1.Now()
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns", Generation: 2},
+		Status: miniov2.TenantStatus{
+			CurrentState: StatusInitialized,
+			HealthStatus: miniov2.HealthStatusGreen,
+			Pools:        []miniov2.PoolStatus{{SSName: "tenant-pool-0", State: miniov2.PoolInitialized}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		update   func(tenant *miniov2.Tenant)
+		expected bool
+	}{
+		{
+			name: "Health checked",
+			update: func(tenant *miniov2.Tenant) {
+				tenant.Status.HealthCheckedAt = &checkedAt
+				tenant.Status.DrivesOnline = 4
+				tenant.Status.Pools[0].UsedBytes = 1024
+			},
+			expected: true,
+		},
+		{
+			name: "Health check failed",
+			update: func(tenant *miniov2.Tenant) {
+				tenant.Status.HealthCheckedAt = &checkedAt
+				tenant.Status.HealthCheckError = "connection refused"
+				tenant.Status.HealthCheckFailures = 1
+			},
+			expected: true,
+		},
+		{
+			name: "Health changed",
+			update: func(tenant *miniov2.Tenant) {
+				tenant.Status.HealthStatus = miniov2.HealthStatusYellow
+			},
+			expected: false,
+		},
+		{
+			name: "Spec changed",
+			update: func(tenant *miniov2.Tenant) {
+				tenant.Generation++
+			},
+			expected: false,
+		},
+		{
+			name: "Paused",
+			update: func(tenant *miniov2.Tenant) {
+				tenant.Annotations = map[string]string{miniov2.PausedAnnotation: "true"}
+			},
+			expected: false,
+		},
+		{
+			name: "Synced",
+			update: func(tenant *miniov2.Tenant) {
+				tenant.Status.HealthCheckedAt = &checkedAt
+				tenant.Status.Pools[0].State = miniov2.PoolDecommissioned
+			},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := tenant.DeepCopy()
+			tt.update(updated)
+			if actual := healthStatusOnlyChanged(tenant, updated); actual != tt.expected {
+				t.Errorf("healthStatusOnlyChanged() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_updateHealthStatusConflict(t *testing.T) {
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Status: miniov2.TenantStatus{
+			CurrentState: StatusInitialized,
+			Pools:        []miniov2.PoolStatus{{SSName: "tenant-pool-0", State: miniov2.PoolInitialized}},
+		},
+	}
+	// the tenant was synced while its health was checked
+	synced := tenant.DeepCopy()
+	synced.Status.CurrentState = StatusUpdatingMinIOVersion
+	client := fakeminio.NewSimpleClientset(synced)
+	conflicted := false
+	client.PrependReactor("update", "tenants", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "status" || conflicted {
+			return false, nil, nil
+		}
+		conflicted = true
+		return true, nil, k8serrors.NewConflict(miniov2.Resource("tenants"), tenant.Name, errors.New("the object has been modified"))
+	})
+
+	checkedAt := metav1.Now()
+	checked := tenant.DeepCopy()
+	checked.Status.HealthCheckedAt = &checkedAt
+	checked.Status.HealthCheckError = "connection refused"
+	checked.Status.HealthCheckFailures = 2
+	checked.Status.Pools[0].DrivesOffline = 1
+	setTenantConditions(checked, newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, miniov2.ReasonUntrustedCertificate, "untrusted"))
+
+	c := &Controller{minioClientSet: client}
+	if _, err := c.updateHealthStatus(context.Background(), checked); err != nil {
+		t.Fatalf("updateHealthStatus() error = %v", err)
+	}
+	if !conflicted {
+		t.Fatalf("updateHealthStatus() didn't hit a conflict")
+	}
+	actual, err := client.MinioV2().Tenants("ns").Get(context.Background(), "tenant", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Status.CurrentState != StatusUpdatingMinIOVersion {
+		t.Errorf("currentState = %q, expected the synced %q", actual.Status.CurrentState, StatusUpdatingMinIOVersion)
+	}
+	if actual.Status.HealthCheckedAt == nil || actual.Status.HealthCheckError != "connection refused" || actual.Status.HealthCheckFailures != 2 {
+		t.Errorf("health check = %v, %q, %d, expected the checked health", actual.Status.HealthCheckedAt, actual.Status.HealthCheckError, actual.Status.HealthCheckFailures)
+	}
+	if actual.Status.Pools[0].DrivesOffline != 1 {
+		t.Errorf("pool drivesOffline = %d, expected 1", actual.Status.Pools[0].DrivesOffline)
+	}
+	if degraded := meta.FindStatusCondition(actual.Status.Conditions, miniov2.TenantConditionDegraded); degraded == nil || degraded.Reason != miniov2.ReasonUntrustedCertificate {
+		t.Errorf("Degraded condition = %v, expected %s", degraded, miniov2.ReasonUntrustedCertificate)
+	}
+}
+
+func Test_getHealthCheckTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	trusted := x509.NewCertPool()
+	trusted.AddCert(server.Certificate())
+
+	tests := []struct {
+		name      string
+		rootCAs   *x509.CertPool
+		untrusted bool
+	}{
+		{
+			name:      "Unknown CA",
+			rootCAs:   x509.NewCertPool(),
+			untrusted: true,
+		},
+		{
+			name:    "Trusted CA",
+			rootCAs: trusted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: getHealthCheckTransport(tt.rootCAs)}
+			defer client.CloseIdleConnections()
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if actual := isCertificateVerificationError(err); actual != tt.untrusted {
+				t.Errorf("isCertificateVerificationError() = %v, expected %v (%v)", actual, tt.untrusted, err)
+			}
+			if !tt.untrusted && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
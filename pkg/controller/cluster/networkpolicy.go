@@ -0,0 +1,96 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/networkpolicies"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// checkNetworkPolicies creates the NetworkPolicies of the tenant when `spec.networkPolicy` is set, keeps them in sync
+// with the tenant spec and removes the ones the tenant no longer needs
+func (c *Controller) checkNetworkPolicies(ctx context.Context, tenant *miniov2.Tenant) error {
+	client := c.kubeClientSet.NetworkingV1().NetworkPolicies(tenant.Namespace)
+	expectedPolicies := map[string]*networkingv1.NetworkPolicy{}
+	if tenant.Spec.NetworkPolicy != nil {
+		for _, policy := range networkpolicies.NewForTenant(tenant) {
+			expectedPolicies[policy.Name] = policy
+		}
+	}
+
+	policies, err := client.List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(tenant.MinIOPodLabels()).String()})
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if !metav1.IsControlledBy(policy, tenant) {
+			continue
+		}
+		expected, ok := expectedPolicies[policy.Name]
+		if !ok {
+			klog.V(2).Infof("Deleting NetworkPolicy %s/%s", policy.Namespace, policy.Name)
+			if err = client.Delete(ctx, policy.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		existing[policy.Name] = true
+		if networkPolicyMatchesSpec(policy, expected) {
+			continue
+		}
+		klog.Infof("NetworkPolicy %s/%s drifted from the tenant specification, updating it", policy.Namespace, policy.Name)
+		policy = policy.DeepCopy()
+		policy.Labels = miniov2.MergeMaps(miniov2.MergeMaps(map[string]string{}, policy.Labels), expected.Labels)
+		policy.Spec = expected.Spec
+		if _, err = client.Update(ctx, policy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	for name, expected := range expectedPolicies {
+		if existing[name] {
+			continue
+		}
+		klog.V(2).Infof("Creating NetworkPolicy %s/%s", expected.Namespace, name)
+		_, err = client.Create(ctx, expected, metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			// a policy the tenant doesn't own, or whose labels were removed
+			klog.Warningf("NetworkPolicy %s/%s already exists and is not managed for the tenant", expected.Namespace, name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// networkPolicyMatchesSpec returns true if the policy carries the labels and the spec of the expected policy
+func networkPolicyMatchesSpec(policy, expected *networkingv1.NetworkPolicy) bool {
+	return equality.Semantic.DeepDerivative(expected.Labels, policy.Labels) &&
+		equality.Semantic.DeepEqual(expected.Spec, policy.Spec)
+}
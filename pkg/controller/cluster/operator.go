@@ -44,6 +44,13 @@ const (
 	OperatorTLS = "MINIO_OPERATOR_TLS_ENABLE"
 	// OperatorTLSSecretName is the name of secret created with Operator TLS certs
 	OperatorTLSSecretName = "operator-tls"
+	// OperatorCATLSSecretName is the name of the secret with a custom CA for the Operator, trusted on connections to tenants
+	OperatorCATLSSecretName = "operator-ca-tls"
+	// OperatorPodIP is the ENV var carrying the IP of the Operator pod, set through the downward API
+	OperatorPodIP = "OPERATOR_POD_IP"
+	// OperatorArtifactsPath is the ENV var pointing to a directory with MinIO images (OCI image layouts or
+	// `docker save` tarballs) used to upgrade tenants without pulling from a registry
+	OperatorArtifactsPath = "MINIO_OPERATOR_ARTIFACTS_PATH"
 )
 
 var (
@@ -56,7 +63,23 @@ func isOperatorTLS() bool {
 	return (set && value == "on") || !set
 }
 
-func (c *Controller) generateTLSCert() (string, string) {
+// operatorUpdateHost returns the host MinIO downloads the update binaries from. Only the leader fetches the binaries,
+// so when the pod IP is known they are served straight from this replica instead of any replica behind the service.
+func operatorUpdateHost() string {
+	if podIP, ok := os.LookupEnv(OperatorPodIP); ok && podIP != "" {
+		return podIP
+	}
+	return fmt.Sprintf("operator.%s.svc.%s", miniov2.GetNSFromFile(), miniov2.GetClusterDomain())
+}
+
+// operatorArtifactsPath returns the directory holding MinIO images for disconnected upgrades, if any
+func operatorArtifactsPath() string {
+	return os.Getenv(OperatorArtifactsPath)
+}
+
+// issueOperatorTLSCert requests the operator TLS certificate until the operator-tls secret exists. Only the leader
+// issues it, so the replicas don't race on the CSR and the secret, the others wait for it in waitForOperatorTLSCert.
+func (c *Controller) issueOperatorTLSCert(stopCh <-chan struct{}) {
 	ctx := context.Background()
 	namespace := miniov2.GetNSFromFile()
 	// operator deployment for owner reference
@@ -65,6 +88,38 @@ func (c *Controller) generateTLSCert() (string, string) {
 		panic(err)
 	}
 
+	for {
+		_, err := c.kubeClientSet.CoreV1().Secrets(namespace).Get(ctx, OperatorTLSSecretName, metav1.GetOptions{})
+		if err == nil {
+			return
+		}
+		if k8serrors.IsNotFound(err) {
+			klog.Infof("operator TLS secret not found")
+			if err = c.checkAndCreateOperatorCSR(ctx, operatorDeployment); err != nil {
+				klog.Infof("Waiting for the operator certificates to be issued %v", err.Error())
+			} else {
+				if err = c.certClient.CertificateSigningRequests().Delete(ctx, "operator-auto-tls", metav1.DeleteOptions{}); err != nil {
+					klog.Infof(err.Error())
+				}
+				continue
+			}
+		} else {
+			klog.Infof("Unable to get the operator TLS secret: %v", err)
+		}
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(time.Second * 10):
+		}
+	}
+}
+
+// waitForOperatorTLSCert waits for the leader to store the operator TLS certificate in the operator-tls secret and
+// writes it out for the webhook server
+func (c *Controller) waitForOperatorTLSCert() (string, string) {
+	ctx := context.Background()
+	namespace := miniov2.GetNSFromFile()
+
 	publicCertPath := "/tmp/public.crt"
 	publicKeyPath := "/tmp/private.key"
 
@@ -73,36 +128,31 @@ func (c *Controller) generateTLSCert() (string, string) {
 		operatorTLSCert, err := c.kubeClientSet.CoreV1().Secrets(namespace).Get(ctx, OperatorTLSSecretName, metav1.GetOptions{})
 		if err != nil {
 			if k8serrors.IsNotFound(err) {
-				klog.Infof("operator TLS secret not found", err.Error())
-				if err = c.checkAndCreateOperatorCSR(ctx, operatorDeployment); err != nil {
-					klog.Infof("Waiting for the operator certificates to be issued %v", err.Error())
-					time.Sleep(time.Second * 10)
-				} else {
-					if err = c.certClient.CertificateSigningRequests().Delete(ctx, "operator-auto-tls", metav1.DeleteOptions{}); err != nil {
-						klog.Infof(err.Error())
-					}
-				}
-			}
-		} else {
-			if val, ok := operatorTLSCert.Data["public.crt"]; ok {
-				err := ioutil.WriteFile(publicCertPath, val, 0644)
-				if err != nil {
-					panic(err)
-				}
+				klog.Infof("Waiting for the leader to issue the operator TLS certificate")
 			} else {
-				panic(errors.New("operator TLS wrong format"))
+				klog.Infof("Unable to get the operator TLS secret: %v", err)
 			}
+			time.Sleep(time.Second * 10)
+			continue
+		}
+		if val, ok := operatorTLSCert.Data["public.crt"]; ok {
+			err := ioutil.WriteFile(publicCertPath, val, 0644)
+			if err != nil {
+				panic(err)
+			}
+		} else {
+			panic(errors.New("operator TLS wrong format"))
+		}
 
-			if val, ok := operatorTLSCert.Data["private.key"]; ok {
-				err := ioutil.WriteFile(publicKeyPath, val, 0644)
-				if err != nil {
-					panic(err)
-				}
-			} else {
-				panic(errors.New("operator TLS wrong format"))
+		if val, ok := operatorTLSCert.Data["private.key"]; ok {
+			err := ioutil.WriteFile(publicKeyPath, val, 0644)
+			if err != nil {
+				panic(err)
 			}
-			break
+		} else {
+			panic(errors.New("operator TLS wrong format"))
 		}
+		break
 	}
 
 	return publicCertPath, publicKeyPath
@@ -0,0 +1,144 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Event reasons reported while the reconciliation of a tenant is paused
+const (
+	ReconciliationPaused  = "ReconciliationPaused"
+	ReconciliationResumed = "ReconciliationResumed"
+	ChangeSkipped         = "ChangeSkipped"
+)
+
+// observeTenant is the paused counterpart of syncHandler. Nothing is changed on behalf of the tenant, the changes
+// syncHandler would make are only reported as events.
+func (c *Controller) observeTenant(ctx context.Context, tenant *miniov2.Tenant) error {
+	tenant.EnsureDefaults()
+	if !meta.IsStatusConditionTrue(tenant.Status.Conditions, miniov2.TenantConditionPaused) {
+		klog.Infof("Reconciliation of Tenant '%s/%s' is paused", tenant.Namespace, tenant.Name)
+		c.recorder.Event(tenant, corev1.EventTypeNormal, ReconciliationPaused, "Reconciliation paused, the Operator only reports the changes it would make")
+	}
+
+	changes, err := c.pendingChanges(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		c.recorder.Event(tenant, corev1.EventTypeNormal, ChangeSkipped, fmt.Sprintf("Paused, would %s", change))
+	}
+	_, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionPaused, metav1.ConditionTrue, miniov2.ReasonPaused,
+		fmt.Sprintf("Reconciliation paused, %d changes pending", len(changes))))
+	return err
+}
+
+// resumeTenant clears the Paused condition once the tenant is no longer paused
+func (c *Controller) resumeTenant(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	if meta.FindStatusCondition(tenant.Status.Conditions, miniov2.TenantConditionPaused) == nil {
+		return tenant, nil
+	}
+	klog.Infof("Reconciliation of Tenant '%s/%s' resumed", tenant.Namespace, tenant.Name)
+	c.recorder.Event(tenant, corev1.EventTypeNormal, ReconciliationResumed, "Reconciliation resumed")
+	return c.removeTenantCondition(ctx, tenant, miniov2.TenantConditionPaused)
+}
+
+// pendingChanges describes the changes syncHandler would make to the tenant resources, without making them
+func (c *Controller) pendingChanges(ctx context.Context, tenant *miniov2.Tenant) ([]string, error) {
+	var changes []string
+	for _, expected := range tenantServices(tenant) {
+		svc, err := c.serviceLister.Services(tenant.Namespace).Get(expected.Name)
+		switch {
+		case k8serrors.IsNotFound(err):
+			changes = append(changes, fmt.Sprintf("create the Service %s", expected.Name))
+		case err != nil:
+			return nil, err
+		case !serviceMatchesSpec(svc, expected):
+			changes = append(changes, fmt.Sprintf("update the Service %s that drifted from the tenant spec", svc.Name))
+		}
+	}
+
+	for _, poolStatus := range tenant.Status.Pools {
+		if poolStatus.MigratingTo == "" && poolStatus.State != miniov2.PoolDecommissioned && !poolInSpec(tenant, poolStatus.SSName) {
+			changes = append(changes, fmt.Sprintf("decommission the pool of StatefulSet %s", poolStatus.SSName))
+		}
+	}
+
+	for i := range tenant.Spec.Pools {
+		pool := &tenant.Spec.Pools[i]
+		ssName := tenant.MinIOStatefulSetNameForPool(pool)
+		ss, err := c.statefulSetLister.StatefulSets(tenant.Namespace).Get(ssName)
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, err
+			}
+			changes = append(changes, fmt.Sprintf("create the StatefulSet %s for pool %s", ssName, pool.Name))
+			continue
+		}
+		if pool.Servers != *ss.Spec.Replicas {
+			changes = append(changes, fmt.Sprintf("migrate pool %s from %d to %d servers", pool.Name, *ss.Spec.Replicas, pool.Servers))
+		}
+		if image := ss.Spec.Template.Spec.Containers[0].Image; image != tenant.TargetImage() {
+			changes = append(changes, fmt.Sprintf("update pool %s from %s to %s", pool.Name, image, tenant.TargetImage()))
+		}
+		if pool.VolumeClaimTemplate != nil {
+			requested := pool.VolumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]
+			if current, ok := poolVolumeRequest(pool, ss); ok && requested.Cmp(current) > 0 {
+				changes = append(changes, fmt.Sprintf("expand the volumes of pool %s from %s to %s", pool.Name, current.String(), requested.String()))
+			}
+		}
+		matches, err := poolSSMatchesSpec(tenant, pool, ss, c.operatorVersion)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			changes = append(changes, fmt.Sprintf("update the StatefulSet %s of pool %s", ss.Name, pool.Name))
+		}
+	}
+
+	if tenant.HasConsoleEnabled() {
+		deployment, err := c.deploymentLister.Deployments(tenant.Namespace).Get(tenant.ConsoleDeploymentName())
+		switch {
+		case k8serrors.IsNotFound(err):
+			changes = append(changes, fmt.Sprintf("create the Console Deployment %s and the tenant users", tenant.ConsoleDeploymentName()))
+		case err != nil:
+			return nil, err
+		default:
+			matches, err := consoleDeploymentMatchesSpec(tenant, deployment)
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				changes = append(changes, fmt.Sprintf("update the Console Deployment %s", deployment.Name))
+			}
+		}
+	}
+
+	if tenant.RestartRequested() {
+		changes = append(changes, "restart the MinIO pods")
+	}
+	return changes, nil
+}
@@ -23,13 +23,14 @@ import (
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	"github.com/minio/operator/pkg/resources/statefulsets"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog/v2"
 )
 
 func (c *Controller) getSSForPool(tenant *miniov2.Tenant, pool *miniov2.Pool) (*appsv1.StatefulSet, error) {
-	ss, err := c.statefulSetLister.StatefulSets(tenant.Namespace).Get(tenant.PoolStatefulsetName(pool))
+	ss, err := c.statefulSetLister.StatefulSets(tenant.Namespace).Get(tenant.MinIOStatefulSetNameForPool(pool))
 	if err != nil {
 		if !k8serrors.IsNotFound(err) {
 			return nil, err
@@ -71,6 +72,22 @@ func poolSSMatchesSpec(tenant *miniov2.Tenant, pool *miniov2.Pool, ss *appsv1.St
 		klog.V(4).Infof("affinity update for pool %s", pool.Name)
 		poolMatchesSS = false
 	}
+	// Verify topology spread constraints, including the spread of zone aware pools
+	if !equality.Semantic.DeepEqual(statefulsets.PoolTopologySpreadConstraints(tenant, pool), ss.Spec.Template.Spec.TopologySpreadConstraints) {
+		klog.V(4).Infof("topology spread constraints update for pool %s", pool.Name)
+		poolMatchesSS = false
+	}
+	// Verify the probes and lifecycle hooks of the MinIO container
+	if !minioContainerHooksMatch(tenant, pool, &ss.Spec.Template.Spec.Containers[0]) {
+		klog.V(4).Infof("probes or lifecycle update for pool %s", pool.Name)
+		poolMatchesSS = false
+	}
+	// Verify the init containers, Kubernetes defaults some of their fields
+	if len(pool.InitContainers) != len(ss.Spec.Template.Spec.InitContainers) ||
+		!equality.Semantic.DeepDerivative(pool.InitContainers, ss.Spec.Template.Spec.InitContainers) {
+		klog.V(4).Infof("init containers update for pool %s", pool.Name)
+		poolMatchesSS = false
+	}
 	// Verify all sidecars
 	if tenant.Spec.SideCars != nil {
 		if len(ss.Spec.Template.Spec.Containers) != len(tenant.Spec.SideCars.Containers)+1 {
@@ -116,3 +133,24 @@ func poolSSMatchesSpec(tenant *miniov2.Tenant, pool *miniov2.Pool, ss *appsv1.St
 	}
 	return poolMatchesSS, nil
 }
+
+// minioContainerHooksMatch returns true if the MinIO container carries the probes and lifecycle hooks of the pool. The
+// probes set by the pool are compared as derivatives since Kubernetes defaults the fields they don't set.
+func minioContainerHooksMatch(tenant *miniov2.Tenant, pool *miniov2.Pool, container *corev1.Container) bool {
+	probes := []struct {
+		expected, current *corev1.Probe
+	}{
+		{statefulsets.MinIOLivenessProbe(tenant, pool), container.LivenessProbe},
+		{statefulsets.MinIOReadinessProbe(tenant, pool), container.ReadinessProbe},
+		{statefulsets.MinIOStartupProbe(tenant, pool), container.StartupProbe},
+	}
+	for _, probe := range probes {
+		if probe.current == nil || !equality.Semantic.DeepDerivative(probe.expected, probe.current) {
+			return false
+		}
+	}
+	if pool.Lifecycle == nil {
+		return container.Lifecycle == nil
+	}
+	return equality.Semantic.DeepDerivative(pool.Lifecycle, container.Lifecycle)
+}
@@ -22,12 +22,28 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/statefulsets"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_poolSSMatchesSpec(t *testing.T) {
+	// the MinIO container of a deployed pool carries the default probes
+	minioContainer := func(tenant *miniov2.Tenant, pool *miniov2.Pool) corev1.Container {
+		return corev1.Container{
+			Name:           "minio",
+			LivenessProbe:  statefulsets.MinIOLivenessProbe(tenant, pool),
+			ReadinessProbe: statefulsets.MinIOReadinessProbe(tenant, pool),
+			StartupProbe:   statefulsets.MinIOStartupProbe(tenant, pool),
+		}
+	}
+	unchangedTenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-a",
+		},
+		Spec: miniov2.TenantSpec{},
+	}
 	type args struct {
 		tenant          *miniov2.Tenant
 		pool            *miniov2.Pool
@@ -43,14 +59,49 @@ func Test_poolSSMatchesSpec(t *testing.T) {
 		{
 			name: "Tenant Unchanged",
 			args: args{
-				tenant: &miniov2.Tenant{
+				tenant: unchangedTenant,
+				pool: &miniov2.Pool{
+					Name: "pool-0",
+				},
+				ss: &appsv1.StatefulSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "tenant-a",
+						Name: "tenant-a-pool-0",
+						Labels: map[string]string{
+							miniov2.PoolLabel:     "pool-0",
+							miniov2.TenantLabel:   "tenant-a",
+							miniov2.OperatorLabel: "0.1",
+						},
+						Annotations: map[string]string{
+							miniov2.Revision: "0",
+						},
+					},
+					Spec: appsv1.StatefulSetSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									minioContainer(unchangedTenant, &miniov2.Pool{Name: "pool-0"}),
+								},
+							},
+						},
 					},
-					Spec: miniov2.TenantSpec{},
 				},
+				operatorVersion: "0.1",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "Startup probe changed",
+			args: args{
+				tenant: unchangedTenant,
 				pool: &miniov2.Pool{
 					Name: "pool-0",
+					StartupProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							HTTPGet: &corev1.HTTPGetAction{Path: miniov2.MinIOLivenessPath},
+						},
+						FailureThreshold: 120,
+					},
 				},
 				ss: &appsv1.StatefulSet{
 					ObjectMeta: metav1.ObjectMeta{
@@ -68,9 +119,7 @@ func Test_poolSSMatchesSpec(t *testing.T) {
 						Template: corev1.PodTemplateSpec{
 							Spec: corev1.PodSpec{
 								Containers: []corev1.Container{
-									{
-										Name: "minio",
-									},
+									minioContainer(unchangedTenant, &miniov2.Pool{Name: "pool-0"}),
 								},
 							},
 						},
@@ -78,7 +127,7 @@ func Test_poolSSMatchesSpec(t *testing.T) {
 				},
 				operatorVersion: "0.1",
 			},
-			want:    true,
+			want:    false,
 			wantErr: false,
 		},
 		{
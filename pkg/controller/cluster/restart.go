@@ -0,0 +1,117 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Event reasons reported during a rolling restart
+const (
+	PodRestarted     = "PodRestarted"
+	RestartCompleted = "RestartCompleted"
+)
+
+// rollingRestart restarts the MinIO pods running when the restart requested through `spec.restartRequestedAt` was
+// picked up, one pod per call. The pods are tracked by UID, `spec.restartRequestedAt` is set with the clock of the
+// client so it can't be compared with the creation time of the pods. The next pod is only deleted once every pod is
+// ready and the server running in the pod reports the cluster keeps its write quorum without it. The StatefulSet status
+// changes caused by each restart enqueue the tenant again.
+func (c *Controller) rollingRestart(ctx context.Context, tenant *miniov2.Tenant, totalReplicas int32) (*miniov2.Tenant, error) {
+	pods, err := c.kubeClientSet.CoreV1().Pods(tenant.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s", miniov2.TenantLabel, tenant.Name, miniov2.PoolLabel),
+	})
+	if err != nil {
+		return tenant, err
+	}
+
+	restart := tenant.Status.Restart
+	if restart == nil || !restart.RequestedAt.Equal(tenant.Spec.RestartRequestedAt) {
+		restart = &miniov2.RestartStatus{RequestedAt: *tenant.Spec.RestartRequestedAt}
+		for _, pod := range pods.Items {
+			restart.PendingPods = append(restart.PendingPods, string(pod.UID))
+		}
+		if tenant, err = c.updateRestartStatus(ctx, tenant, tenant.Status.RestartedAt, restart); err != nil {
+			return tenant, err
+		}
+	}
+	pendingPods := make(map[string]bool)
+	for _, uid := range restart.PendingPods {
+		pendingPods[uid] = true
+	}
+
+	var pending []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || !isPodReady(&pod) {
+			klog.V(2).Infof("Waiting for pod %s to be ready before restarting the next MinIO pod", pod.Name)
+			return c.updateTenantStatus(ctx, tenant, StatusRestartingMinIO, totalReplicas)
+		}
+		if pendingPods[string(pod.UID)] {
+			pending = append(pending, pod)
+		}
+	}
+
+	if len(pending) == 0 {
+		c.recorder.Event(tenant, corev1.EventTypeNormal, RestartCompleted, "All MinIO pods were restarted")
+		return c.updateRestartStatus(ctx, tenant, tenant.Spec.RestartRequestedAt, nil)
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Name < pending[j].Name
+	})
+	pod := pending[0]
+
+	// Ask the server about to be restarted if it can be taken down without losing write quorum
+	rootCAs, err := c.getTenantRootCAs(ctx, tenant)
+	if err != nil {
+		return tenant, err
+	}
+	health, err := getMinIOPodHealthStatus(ctx, tenant, pod.Name, rootCAs, MaintenanceMode)
+	if err != nil {
+		return tenant, err
+	}
+	if health.StatusCode != http.StatusOK {
+		klog.Infof("Tenant '%s/%s' can't tolerate the restart of pod %s at the moment, waiting", tenant.Namespace, tenant.Name, pod.Name)
+		return c.updateTenantStatus(ctx, tenant, StatusWaitingSafeRestart, totalReplicas)
+	}
+
+	klog.Infof("Restarting pod %s of Tenant '%s/%s'", pod.Name, tenant.Namespace, tenant.Name)
+	if err = c.kubeClientSet.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return tenant, err
+	}
+	c.recorder.Event(tenant, corev1.EventTypeNormal, PodRestarted, fmt.Sprintf("Restarted pod %s, %d pods left", pod.Name, len(pending)-1))
+	return c.updateTenantStatus(ctx, tenant, StatusRestartingMinIO, totalReplicas)
+}
+
+// isPodReady returns true if the pod reports the Ready condition
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
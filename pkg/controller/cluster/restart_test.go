@@ -0,0 +1,121 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	fakeminio "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_rollingRestart(t *testing.T) {
+	now := time.Now()
+	// the clock of the client requesting the restart is an hour off the clock of the API server
+	requestedAt := metav1.NewTime(now.Add(-time.Hour))
+	pod := func(name, uid string, createdAt time.Time, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ns",
+				UID:               types.UID(uid),
+				CreationTimestamp: metav1.NewTime(createdAt),
+				Labels:            map[string]string{miniov2.TenantLabel: "tenant", miniov2.PoolLabel: "pool-0"},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		restart           *miniov2.RestartStatus
+		pods              []*corev1.Pod
+		expectedRestart   *miniov2.RestartStatus
+		expectedRestarted bool
+	}{
+		{
+			name: "Pods created after the requested time are restarted",
+			pods: []*corev1.Pod{
+				pod("tenant-pool-0-0", "uid-0", now, corev1.ConditionTrue),
+				pod("tenant-pool-0-1", "uid-1", now, corev1.ConditionFalse),
+			},
+			expectedRestart: &miniov2.RestartStatus{RequestedAt: requestedAt, PendingPods: []string{"uid-0", "uid-1"}},
+		},
+		{
+			name:    "Pods created before the requested time were restarted",
+			restart: &miniov2.RestartStatus{RequestedAt: requestedAt, PendingPods: []string{"uid-0", "uid-1"}},
+			pods: []*corev1.Pod{
+				pod("tenant-pool-0-0", "uid-2", now.Add(-2*time.Hour), corev1.ConditionTrue),
+				pod("tenant-pool-0-1", "uid-3", now.Add(-2*time.Hour), corev1.ConditionTrue),
+			},
+			expectedRestarted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &miniov2.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+				Spec:       miniov2.TenantSpec{RestartRequestedAt: &requestedAt},
+				Status:     miniov2.TenantStatus{Restart: tt.restart},
+			}
+			kubeClient := fake.NewSimpleClientset()
+			for _, pod := range tt.pods {
+				if err := kubeClient.Tracker().Add(pod); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := &Controller{
+				kubeClientSet:  kubeClient,
+				minioClientSet: fakeminio.NewSimpleClientset(tenant),
+				recorder:       record.NewFakeRecorder(10),
+			}
+
+			actual, err := c.rollingRestart(context.Background(), tenant, 2)
+			if err != nil {
+				t.Fatalf("rollingRestart() error = %v", err)
+			}
+			if restarted := !actual.RestartRequested(); restarted != tt.expectedRestarted {
+				t.Errorf("rollingRestart() restarted = %v, expected %v", restarted, tt.expectedRestarted)
+			}
+			switch {
+			case tt.expectedRestart == nil && actual.Status.Restart != nil:
+				t.Errorf("rollingRestart() restart = %v, expected none", actual.Status.Restart)
+			case tt.expectedRestart != nil && (actual.Status.Restart == nil ||
+				!actual.Status.Restart.RequestedAt.Equal(&tt.expectedRestart.RequestedAt) ||
+				len(actual.Status.Restart.PendingPods) != len(tt.expectedRestart.PendingPods)):
+				t.Errorf("rollingRestart() restart = %v, expected %v", actual.Status.Restart, tt.expectedRestart)
+			}
+			pods, err := kubeClient.CoreV1().Pods("ns").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pods.Items) != len(tt.pods) {
+				t.Errorf("rollingRestart() deleted %d pods, expected none", len(tt.pods)-len(pods.Items))
+			}
+		})
+	}
+}
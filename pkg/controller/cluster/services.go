@@ -0,0 +1,172 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/services"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+)
+
+// serviceResource is the resource of the Kubernetes services, written through the dynamic client to set fields the
+// Service type of the Kubernetes API version the Operator is built with doesn't have
+var serviceResource = corev1.SchemeGroupVersion.WithResource("services")
+
+// tenantServices returns the services the tenant owns, as rendered by the services builders
+func tenantServices(tenant *miniov2.Tenant) []*corev1.Service {
+	svcs := []*corev1.Service{
+		services.NewClusterIPForMinIO(tenant),
+		services.NewHeadlessForMinIO(tenant),
+	}
+	if tenant.HasConsoleEnabled() {
+		svcs = append(svcs, services.NewClusterIPForConsole(tenant))
+	}
+	if tenant.HasKESEnabled() {
+		svcs = append(svcs, services.NewHeadlessForKES(tenant))
+	}
+	if tenant.HasLogEnabled() {
+		svcs = append(svcs, services.NewHeadlessForLog(tenant), services.NewClusterIPForLogSearchAPI(tenant))
+	}
+	if tenant.HasPrometheusEnabled() {
+		svcs = append(svcs, services.NewHeadlessForPrometheus(tenant))
+	}
+	return svcs
+}
+
+// syncService updates the service when it drifted from the service rendered for the tenant. Labels and annotations
+// added by others are kept, as well as the ClusterIP and NodePorts allocated to the service.
+func (c *Controller) syncService(ctx context.Context, tenant *miniov2.Tenant, svc, expected *corev1.Service) (*corev1.Service, error) {
+	if serviceMatchesSpec(svc, expected) {
+		return svc, nil
+	}
+	klog.Infof("Service %s/%s drifted from the tenant specification, updating it", svc.Namespace, svc.Name)
+	svcCopy := svc.DeepCopy()
+	svcCopy.Labels = miniov2.MergeMaps(miniov2.MergeMaps(map[string]string{}, svc.Labels), expected.Labels)
+	svcCopy.Annotations = miniov2.MergeMaps(miniov2.MergeMaps(map[string]string{}, svc.Annotations), expected.Annotations)
+	svcCopy.Spec = desiredServiceSpec(svc, expected)
+	class := services.LoadBalancerClass(tenant, svcCopy)
+	if class == "" {
+		return c.kubeClientSet.CoreV1().Services(svc.Namespace).Update(ctx, svcCopy, metav1.UpdateOptions{})
+	}
+	// the load balancer class is immutable, an update without it would be rejected
+	obj, err := serviceWithLoadBalancerClass(svcCopy, class)
+	if err != nil {
+		return nil, err
+	}
+	if obj, err = c.dynamicClient.Resource(serviceResource).Namespace(svc.Namespace).Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+	return serviceFromUnstructured(obj)
+}
+
+// createService creates a service of the tenant, with the load balancer class it is exposed with if any
+func (c *Controller) createService(ctx context.Context, tenant *miniov2.Tenant, svc *corev1.Service) (*corev1.Service, error) {
+	class := services.LoadBalancerClass(tenant, svc)
+	if class == "" {
+		return c.kubeClientSet.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+	}
+	obj, err := serviceWithLoadBalancerClass(svc, class)
+	if err != nil {
+		return nil, err
+	}
+	if obj, err = c.dynamicClient.Resource(serviceResource).Namespace(svc.Namespace).Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+	return serviceFromUnstructured(obj)
+}
+
+// serviceWithLoadBalancerClass converts the service to an unstructured object with `spec.loadBalancerClass` set
+func serviceWithLoadBalancerClass(svc *corev1.Service, class string) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(svc)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion(corev1.SchemeGroupVersion.String())
+	obj.SetKind("Service")
+	if err = unstructured.SetNestedField(obj.Object, class, "spec", "loadBalancerClass"); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func serviceFromUnstructured(obj *unstructured.Unstructured) (*corev1.Service, error) {
+	svc := &corev1.Service{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, svc)
+	return svc, err
+}
+
+// serviceMatchesSpec returns true if the service carries the labels, annotations and spec of the expected service
+func serviceMatchesSpec(svc, expected *corev1.Service) bool {
+	if !equality.Semantic.DeepDerivative(expected.Labels, svc.Labels) ||
+		!equality.Semantic.DeepDerivative(expected.Annotations, svc.Annotations) {
+		return false
+	}
+	// a derivative comparison would miss selectors and ports added to the service
+	if !equality.Semantic.DeepEqual(expected.Spec.Selector, svc.Spec.Selector) || len(expected.Spec.Ports) != len(svc.Spec.Ports) {
+		return false
+	}
+	return equality.Semantic.DeepDerivative(desiredServiceSpec(svc, expected), svc.Spec)
+}
+
+// desiredServiceSpec returns the spec of the expected service completed with the fields Kubernetes allocates and
+// defaults on the existing service, so both can be compared and the service updated without releasing its ClusterIP
+// or NodePorts
+func desiredServiceSpec(svc, expected *corev1.Service) corev1.ServiceSpec {
+	spec := *expected.Spec.DeepCopy()
+	spec.ClusterIP = svc.Spec.ClusterIP
+	spec.ClusterIPs = svc.Spec.ClusterIPs
+	spec.IPFamilies = svc.Spec.IPFamilies
+	spec.IPFamilyPolicy = svc.Spec.IPFamilyPolicy
+	if spec.SessionAffinity == "" {
+		spec.SessionAffinity = corev1.ServiceAffinityNone
+	}
+	exposed := spec.Type == corev1.ServiceTypeNodePort || spec.Type == corev1.ServiceTypeLoadBalancer
+	if exposed && spec.ExternalTrafficPolicy == "" {
+		spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+	}
+	if exposed && spec.HealthCheckNodePort == 0 && spec.ExternalTrafficPolicy == svc.Spec.ExternalTrafficPolicy {
+		spec.HealthCheckNodePort = svc.Spec.HealthCheckNodePort
+	}
+	for i := range spec.Ports {
+		port := &spec.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if port.TargetPort == (intstr.IntOrString{}) {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+		if !exposed || port.NodePort != 0 {
+			continue
+		}
+		for _, current := range svc.Spec.Ports {
+			if current.Name == port.Name {
+				port.NodePort = current.NodePort
+				break
+			}
+		}
+	}
+	return spec
+}
@@ -0,0 +1,124 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/services"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_serviceMatchesSpec(t *testing.T) {
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Spec:       miniov2.TenantSpec{ExposeServices: &miniov2.ExposeServices{MinIO: true}},
+	}
+	tenant.EnsureDefaults()
+	expected := services.NewClusterIPForMinIO(tenant)
+
+	// the service as stored by Kubernetes, with its allocated and defaulted fields
+	deployed := expected.DeepCopy()
+	deployed.Annotations = map[string]string{"metallb.universe.tf/ip-allocated-from-pool": "default"}
+	deployed.Spec.ClusterIP = "10.96.0.10"
+	deployed.Spec.ClusterIPs = []string{"10.96.0.10"}
+	deployed.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	deployed.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+	deployed.Spec.Ports[0].Protocol = corev1.ProtocolTCP
+	deployed.Spec.Ports[0].NodePort = 31000
+
+	if !serviceMatchesSpec(deployed, expected) {
+		t.Fatal("allocated and defaulted fields should not be reported as drift")
+	}
+
+	edited := deployed.DeepCopy()
+	edited.Spec.Selector = miniov2.MergeMaps(map[string]string{"app": "other"}, deployed.Spec.Selector)
+	if serviceMatchesSpec(edited, expected) {
+		t.Error("an added selector should be reported as drift")
+	}
+
+	edited = deployed.DeepCopy()
+	edited.Spec.Type = corev1.ServiceTypeNodePort
+	if serviceMatchesSpec(edited, expected) {
+		t.Error("a changed type should be reported as drift")
+	}
+	spec := desiredServiceSpec(edited, expected)
+	if spec.ClusterIP != "10.96.0.10" || spec.Ports[0].NodePort != 31000 {
+		t.Errorf("the ClusterIP and NodePorts should be preserved, got %s and %d", spec.ClusterIP, spec.Ports[0].NodePort)
+	}
+}
+
+func Test_resolveMinIOServiceName(t *testing.T) {
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns", UID: "tenant-uid"},
+	}
+	legacyService := func(owner *miniov2.Tenant) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            miniov2.LegacyMinIOCIServiceName,
+				Namespace:       "ns",
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, miniov2.SchemeGroupVersion.WithKind(miniov2.MinIOCRDResourceKind))},
+			},
+		}
+	}
+	other := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns", UID: "other-uid"},
+	}
+
+	tests := []struct {
+		name     string
+		service  *corev1.Service
+		expected string
+	}{
+		{
+			name:     "No legacy service",
+			expected: "tenant",
+		},
+		{
+			name:     "Legacy service of the tenant",
+			service:  legacyService(tenant),
+			expected: miniov2.LegacyMinIOCIServiceName,
+		},
+		{
+			name:     "Legacy service of another tenant",
+			service:  legacyService(other),
+			expected: "tenant",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if tt.service != nil {
+				if err := indexer.Add(tt.service); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := &Controller{serviceLister: corelisters.NewServiceLister(indexer)}
+			actual, err := c.resolveMinIOServiceName(tenant)
+			if err != nil {
+				t.Fatalf("resolveMinIOServiceName() error = %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("resolveMinIOServiceName() = %q, expected %q", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 )
@@ -30,9 +31,15 @@ func (c *Controller) updateTenantStatus(ctx context.Context, tenant *miniov2.Ten
 }
 
 func (c *Controller) updateTenantStatusWithRetry(ctx context.Context, tenant *miniov2.Tenant, currentState string, availableReplicas int32, retry bool) (*miniov2.Tenant, error) {
+	// The Ready condition mirrors the current state, the tenant is only ready once it reaches StatusInitialized
+	readyCondition := newTenantCondition(miniov2.TenantConditionReady, metav1.ConditionFalse, miniov2.ReasonReconciling, currentState)
+	if currentState == StatusInitialized {
+		readyCondition = newTenantCondition(miniov2.TenantConditionReady, metav1.ConditionTrue, miniov2.ReasonInitialized, currentState)
+	}
 	// If we are updating the tenant with the same status as before we are going to skip it as to avoid a resource number
 	// change and have the operator loop re-processing the tenant endlessly
-	if tenant.Status.CurrentState == currentState && tenant.Status.AvailableReplicas == availableReplicas {
+	if tenant.Status.CurrentState == currentState && tenant.Status.AvailableReplicas == availableReplicas &&
+		!tenantConditionsChanged(tenant, readyCondition) {
 		return tenant, nil
 	}
 	// NEVER modify objects from the store. It's a read-only, local cache.
@@ -41,6 +48,7 @@ func (c *Controller) updateTenantStatusWithRetry(ctx context.Context, tenant *mi
 	tenantCopy := tenant.DeepCopy()
 	tenantCopy.Status.AvailableReplicas = availableReplicas
 	tenantCopy.Status.CurrentState = currentState
+	setTenantConditions(tenantCopy, readyCondition)
 	// If the CustomResourceSubresources feature gate is not enabled,
 	// we must use Update instead of UpdateStatus to update the Status block of the Tenant resource.
 	// UpdateStatus will not allow changes to the Spec of the resource,
@@ -130,6 +138,34 @@ func (c *Controller) updatePoolStatusWithRetry(ctx context.Context, tenant *mini
 	return t, nil
 }
 
+func (c *Controller) updateHealthStatus(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	return c.updateHealthStatusWithRetry(ctx, tenant, true)
+}
+
+func (c *Controller) updateHealthStatusWithRetry(ctx context.Context, tenant *miniov2.Tenant, retry bool) (*miniov2.Tenant, error) {
+	// NEVER modify objects from the store. It's a read-only, local cache.
+	// You can use DeepCopy() to make a deep copy of original object and modify this copy
+	// Or create a copy manually for better performance
+	tenantCopy := tenant.DeepCopy()
+	opts := metav1.UpdateOptions{}
+	t, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).UpdateStatus(ctx, tenantCopy, opts)
+	if err != nil {
+		// if rejected due to conflict, get the latest tenant and retry once with the health recorded on it
+		if k8serrors.IsConflict(err) && retry {
+			klog.Info("Hit conflict issue, getting latest version of tenant")
+			latest, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+			if err != nil {
+				return tenant, err
+			}
+			setHealthStatus(latest, tenant)
+			return c.updateHealthStatusWithRetry(ctx, latest, false)
+		}
+		return t, err
+	}
+	t.EnsureDefaults()
+	return t, nil
+}
+
 func (c *Controller) updateCertificatesStatus(ctx context.Context, tenant *miniov2.Tenant, autoCertEnabled bool) (*miniov2.Tenant, error) {
 	return c.updateCertificatesWithRetry(ctx, tenant, autoCertEnabled, true)
 }
@@ -163,3 +199,242 @@ func (c *Controller) updateCertificatesWithRetry(ctx context.Context, tenant *mi
 	}
 	return t, nil
 }
+
+func (c *Controller) updateRestartStatus(ctx context.Context, tenant *miniov2.Tenant, restartedAt *metav1.Time, restart *miniov2.RestartStatus) (*miniov2.Tenant, error) {
+	return c.updateRestartStatusWithRetry(ctx, tenant, restartedAt, restart, true)
+}
+
+func (c *Controller) updateRestartStatusWithRetry(ctx context.Context, tenant *miniov2.Tenant, restartedAt *metav1.Time, restart *miniov2.RestartStatus, retry bool) (*miniov2.Tenant, error) {
+	tenantCopy := tenant.DeepCopy()
+	tenantCopy.Status = *tenant.Status.DeepCopy()
+	tenantCopy.Status.RestartedAt = restartedAt.DeepCopy()
+	tenantCopy.Status.Restart = restart.DeepCopy()
+	opts := metav1.UpdateOptions{}
+	t, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).UpdateStatus(ctx, tenantCopy, opts)
+	if err != nil {
+		// if rejected due to conflict, get the latest tenant and retry once
+		if k8serrors.IsConflict(err) && retry {
+			klog.Info("Hit conflict issue, getting latest version of tenant")
+			tenant, err = c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+			if err != nil {
+				return tenant, err
+			}
+			return c.updateRestartStatusWithRetry(ctx, tenant, restartedAt, restart, false)
+		}
+		return t, err
+	}
+	t.EnsureDefaults()
+	return t, nil
+}
+
+func (c *Controller) updateMinIOServiceNameStatus(ctx context.Context, tenant *miniov2.Tenant, serviceName string) (*miniov2.Tenant, error) {
+	return c.updateMinIOServiceNameStatusWithRetry(ctx, tenant, serviceName, true)
+}
+
+func (c *Controller) updateMinIOServiceNameStatusWithRetry(ctx context.Context, tenant *miniov2.Tenant, serviceName string, retry bool) (*miniov2.Tenant, error) {
+	tenantCopy := tenant.DeepCopy()
+	tenantCopy.Status = *tenant.Status.DeepCopy()
+	tenantCopy.Status.MinIOServiceName = serviceName
+	opts := metav1.UpdateOptions{}
+	t, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).UpdateStatus(ctx, tenantCopy, opts)
+	t.EnsureDefaults()
+	if err != nil {
+		// if rejected due to conflict, get the latest tenant and retry once
+		if k8serrors.IsConflict(err) && retry {
+			klog.Info("Hit conflict issue, getting latest version of tenant")
+			tenant, err = c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+			if err != nil {
+				return tenant, err
+			}
+			return c.updateMinIOServiceNameStatusWithRetry(ctx, tenant, serviceName, false)
+		}
+		return t, err
+	}
+	return t, nil
+}
+
+// newTenantCondition returns a condition of the given type, the observed generation is filled when it's set on a tenant
+func newTenantCondition(conditionType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// tenantConditionsChanged returns true if any of the conditions is not yet reflected in the tenant status for the
+// current generation of the tenant
+func tenantConditionsChanged(tenant *miniov2.Tenant, conditions ...metav1.Condition) bool {
+	for _, condition := range conditions {
+		current := meta.FindStatusCondition(tenant.Status.Conditions, condition.Type)
+		if current == nil ||
+			current.Status != condition.Status ||
+			current.Reason != condition.Reason ||
+			current.Message != condition.Message ||
+			current.ObservedGeneration != tenant.Generation {
+			return true
+		}
+	}
+	return false
+}
+
+// setTenantConditions sets the conditions on the tenant status, stamping them with the tenant generation. The
+// transition time is only updated when the status of a condition changes.
+func setTenantConditions(tenant *miniov2.Tenant, conditions ...metav1.Condition) {
+	for _, condition := range conditions {
+		condition.ObservedGeneration = tenant.Generation
+		meta.SetStatusCondition(&tenant.Status.Conditions, condition)
+	}
+}
+
+func (c *Controller) updateTenantConditions(ctx context.Context, tenant *miniov2.Tenant, conditions ...metav1.Condition) (*miniov2.Tenant, error) {
+	return c.updateTenantConditionsWithRetry(ctx, tenant, conditions, true)
+}
+
+func (c *Controller) updateTenantConditionsWithRetry(ctx context.Context, tenant *miniov2.Tenant, conditions []metav1.Condition, retry bool) (*miniov2.Tenant, error) {
+	// Skip the update if nothing changed to avoid a resource number change and have the operator loop re-processing
+	// the tenant endlessly
+	if !tenantConditionsChanged(tenant, conditions...) {
+		return tenant, nil
+	}
+	// NEVER modify objects from the store. It's a read-only, local cache.
+	// You can use DeepCopy() to make a deep copy of original object and modify this copy
+	// Or create a copy manually for better performance
+	tenantCopy := tenant.DeepCopy()
+	setTenantConditions(tenantCopy, conditions...)
+	opts := metav1.UpdateOptions{}
+	t, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).UpdateStatus(ctx, tenantCopy, opts)
+	t.EnsureDefaults()
+	if err != nil {
+		// if rejected due to conflict, get the latest tenant and retry once
+		if k8serrors.IsConflict(err) && retry {
+			klog.Info("Hit conflict issue, getting latest version of tenant")
+			tenant, err = c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+			if err != nil {
+				return tenant, err
+			}
+			return c.updateTenantConditionsWithRetry(ctx, tenant, conditions, false)
+		}
+		return t, err
+	}
+	return t, nil
+}
+
+func (c *Controller) removeTenantCondition(ctx context.Context, tenant *miniov2.Tenant, conditionType string) (*miniov2.Tenant, error) {
+	return c.removeTenantConditionWithRetry(ctx, tenant, conditionType, true)
+}
+
+func (c *Controller) removeTenantConditionWithRetry(ctx context.Context, tenant *miniov2.Tenant, conditionType string, retry bool) (*miniov2.Tenant, error) {
+	if meta.FindStatusCondition(tenant.Status.Conditions, conditionType) == nil {
+		return tenant, nil
+	}
+	// NEVER modify objects from the store. It's a read-only, local cache.
+	// You can use DeepCopy() to make a deep copy of original object and modify this copy
+	// Or create a copy manually for better performance
+	tenantCopy := tenant.DeepCopy()
+	meta.RemoveStatusCondition(&tenantCopy.Status.Conditions, conditionType)
+	opts := metav1.UpdateOptions{}
+	t, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).UpdateStatus(ctx, tenantCopy, opts)
+	t.EnsureDefaults()
+	if err != nil {
+		// if rejected due to conflict, get the latest tenant and retry once
+		if k8serrors.IsConflict(err) && retry {
+			klog.Info("Hit conflict issue, getting latest version of tenant")
+			tenant, err = c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+			if err != nil {
+				return tenant, err
+			}
+			return c.removeTenantConditionWithRetry(ctx, tenant, conditionType, false)
+		}
+		return t, err
+	}
+	return t, nil
+}
+
+// configurationDegradedReasons are the reasons for which syncHandler marks a tenant as degraded, the health monitor
+// reports through the same condition but must not clear these
+var configurationDegradedReasons = []string{
+	miniov2.ReasonValidationFailed,
+	miniov2.ReasonInconsistentVersions,
+	miniov2.ReasonNotOwned,
+	miniov2.ReasonVolumeShrinkNotSupported,
+	miniov2.ReasonVolumeExpansionNotSupported,
+}
+
+// degradedByConfiguration returns true if the tenant is degraded due to a configuration problem
+func degradedByConfiguration(tenant *miniov2.Tenant) bool {
+	current := meta.FindStatusCondition(tenant.Status.Conditions, miniov2.TenantConditionDegraded)
+	if current == nil || current.Status != metav1.ConditionTrue {
+		return false
+	}
+	for _, reason := range configurationDegradedReasons {
+		if current.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveTenantDegraded sets the Degraded condition back to false if it was raised due to a configuration problem
+func (c *Controller) resolveTenantDegraded(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	if !degradedByConfiguration(tenant) {
+		return tenant, nil
+	}
+	return c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionFalse, miniov2.ReasonResolved, ""))
+}
+
+func (c *Controller) updateUpgradeHealthStatus(ctx context.Context, tenant *miniov2.Tenant, failures int32, failedAt *metav1.Time) (*miniov2.Tenant, error) {
+	return c.updateUpgradeHealthStatusWithRetry(ctx, tenant, failures, failedAt, true)
+}
+
+func (c *Controller) updateUpgradeHealthStatusWithRetry(ctx context.Context, tenant *miniov2.Tenant, failures int32, failedAt *metav1.Time, retry bool) (*miniov2.Tenant, error) {
+	tenantCopy := tenant.DeepCopy()
+	tenantCopy.Status = *tenant.Status.DeepCopy()
+	tenantCopy.Status.UpgradeHealthFailures = failures
+	tenantCopy.Status.UpgradeHealthFailedAt = failedAt.DeepCopy()
+	opts := metav1.UpdateOptions{}
+	t, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).UpdateStatus(ctx, tenantCopy, opts)
+	t.EnsureDefaults()
+	if err != nil {
+		// if rejected due to conflict, get the latest tenant and retry once
+		if k8serrors.IsConflict(err) && retry {
+			klog.Info("Hit conflict issue, getting latest version of tenant")
+			tenant, err = c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+			if err != nil {
+				return tenant, err
+			}
+			return c.updateUpgradeHealthStatusWithRetry(ctx, tenant, failures, failedAt, false)
+		}
+		return t, err
+	}
+	return t, nil
+}
+
+func (c *Controller) updateUpgradeStatus(ctx context.Context, tenant *miniov2.Tenant, lastKnownGoodImage, rolledBackImage string, upgradedAt *metav1.Time) (*miniov2.Tenant, error) {
+	return c.updateUpgradeStatusWithRetry(ctx, tenant, lastKnownGoodImage, rolledBackImage, upgradedAt, true)
+}
+
+func (c *Controller) updateUpgradeStatusWithRetry(ctx context.Context, tenant *miniov2.Tenant, lastKnownGoodImage, rolledBackImage string, upgradedAt *metav1.Time, retry bool) (*miniov2.Tenant, error) {
+	tenantCopy := tenant.DeepCopy()
+	tenantCopy.Status = *tenant.Status.DeepCopy()
+	tenantCopy.Status.LastKnownGoodImage = lastKnownGoodImage
+	tenantCopy.Status.RolledBackImage = rolledBackImage
+	tenantCopy.Status.UpgradedAt = upgradedAt.DeepCopy()
+	opts := metav1.UpdateOptions{}
+	t, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).UpdateStatus(ctx, tenantCopy, opts)
+	t.EnsureDefaults()
+	if err != nil {
+		// if rejected due to conflict, get the latest tenant and retry once
+		if k8serrors.IsConflict(err) && retry {
+			klog.Info("Hit conflict issue, getting latest version of tenant")
+			tenant, err = c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+			if err != nil {
+				return tenant, err
+			}
+			return c.updateUpgradeStatusWithRetry(ctx, tenant, lastKnownGoodImage, rolledBackImage, upgradedAt, false)
+		}
+		return t, err
+	}
+	return t, nil
+}
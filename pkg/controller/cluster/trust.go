@@ -0,0 +1,112 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// CertificateUntrusted is the event reason reported when the Operator can't verify the certificate of a tenant
+const CertificateUntrusted = "CertificateUntrusted"
+
+// getTenantRootCAs returns the CAs the Operator trusts when it connects to the tenant: the system CAs, the Kubernetes
+// CA signing the certificates issued by the Operator, the Operator CA from the `operator-ca-tls` secret and the CAs of
+// the tenant `spec.externalCaCertSecret`
+func (c *Controller) getTenantRootCAs(ctx context.Context, tenant *miniov2.Tenant) (*x509.CertPool, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	rootCAs.AppendCertsFromPEM(miniov2.GetPodCAFromFile())
+
+	operatorCA, err := c.kubeClientSet.CoreV1().Secrets(miniov2.GetNSFromFile()).Get(ctx, OperatorCATLSSecretName, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		rootCAs.AppendCertsFromPEM(operatorCA.Data["ca.crt"])
+	}
+
+	for _, secret := range tenant.Spec.ExternalCaCertSecret {
+		caSecret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		// the CA certificate is read from the same key it's mounted from into the MinIO pods
+		key := "public.crt"
+		switch secret.Type {
+		case "kubernetes.io/tls":
+			key = "tls.crt"
+		case "cert-manager.io/v1alpha2":
+			key = "ca.crt"
+		}
+		if !rootCAs.AppendCertsFromPEM(caSecret.Data[key]) {
+			return nil, fmt.Errorf("secret %s of externalCaCertSecret has no PEM encoded certificate in %s", secret.Name, key)
+		}
+	}
+	return rootCAs, nil
+}
+
+// isCertificateVerificationError returns true if err is caused by a MinIO certificate the Operator doesn't trust
+func isCertificateVerificationError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	return errors.As(err, &unknownAuthority) || errors.As(err, &invalid) || errors.As(err, &hostname)
+}
+
+// certificateVerificationMessage explains a certificate verification error in the tenant status
+func certificateVerificationMessage(err error) string {
+	return fmt.Sprintf("The MinIO certificate can't be verified, add the CA that signed it to spec.externalCaCertSecret: %v", err)
+}
+
+// checkMinIOHealth returns ErrMinIONotReady unless MinIO reports the cluster healthy. When the MinIO certificate can't
+// be verified, the tenant is marked Degraded so the reason it isn't ready shows in its status.
+func (c *Controller) checkMinIOHealth(ctx context.Context, tenant *miniov2.Tenant, rootCAs *x509.CertPool) (*miniov2.Tenant, error) {
+	err := tenant.MinIOHealthCheck(rootCAs)
+	if err == nil {
+		return tenant, nil
+	}
+	if !isCertificateVerificationError(err) {
+		klog.V(2).Infof("MinIO of Tenant '%s/%s' is not ready: %v", tenant.Namespace, tenant.Name, err)
+		return tenant, ErrMinIONotReady
+	}
+
+	message := certificateVerificationMessage(err)
+	klog.Warningf("MinIO of Tenant '%s/%s' is not ready: %s", tenant.Namespace, tenant.Name, message)
+	if degradedByConfiguration(tenant) {
+		return tenant, ErrMinIONotReady
+	}
+	if current := meta.FindStatusCondition(tenant.Status.Conditions, miniov2.TenantConditionDegraded); current == nil || current.Reason != miniov2.ReasonUntrustedCertificate {
+		c.recorder.Event(tenant, corev1.EventTypeWarning, CertificateUntrusted, message)
+	}
+	updated, err := c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, miniov2.ReasonUntrustedCertificate, message))
+	if err != nil {
+		return tenant, err
+	}
+	return updated, ErrMinIONotReady
+}
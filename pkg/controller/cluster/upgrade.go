@@ -0,0 +1,241 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// UpgradeRolledBack is the event reason reported when a failed upgrade is rolled back
+const UpgradeRolledBack = "UpgradeRolledBack"
+
+const (
+	// upgradeSettleTime is how long MinIO gets to come back after the pools are moved to a new image before the tenant
+	// health counts towards a rollback
+	upgradeSettleTime = time.Minute
+	// upgradeRollbackFailures is the number of consecutive failed health checks that roll an upgrade back
+	upgradeRollbackFailures = 3
+	// upgradeHealthCheckInterval is the minimum time between two failed health checks counting towards a rollback
+	upgradeHealthCheckInterval = 15 * time.Second
+)
+
+// rollingUpgrade moves the pool StatefulSets to the tenant image one pool at a time. The next pool is only updated
+// once the previous pools are fully rolled out and MinIO reports the tenant healthy. It returns true once every pool
+// runs the tenant image, the StatefulSet status changes enqueue the tenant again while the pools roll.
+func (c *Controller) rollingUpgrade(ctx context.Context, tenant *miniov2.Tenant, totalReplicas int32) (*miniov2.Tenant, bool, error) {
+	for i := range tenant.Spec.Pools {
+		pool := &tenant.Spec.Pools[i]
+		ss, err := c.getSSForPool(tenant, pool)
+		if err != nil {
+			return tenant, false, err
+		}
+		currentImage := ss.Spec.Template.Spec.Containers[0].Image
+		if currentImage == tenant.TargetImage() {
+			if !statefulSetRolledOut(ss) {
+				klog.V(2).Infof("Waiting for pool %s of Tenant '%s/%s' to roll out", pool.Name, tenant.Namespace, tenant.Name)
+				tenant, err = c.updateTenantStatus(ctx, tenant, StatusUpdatingMinIOVersion, totalReplicas)
+				return tenant, false, err
+			}
+			continue
+		}
+
+		// Only move to the next pool while the tenant is healthy
+		rootCAs, err := c.getTenantRootCAs(ctx, tenant)
+		if err != nil {
+			return tenant, false, err
+		}
+		health, err := getMinIOHealthStatus(ctx, tenant, rootCAs, RegularMode)
+		if err != nil {
+			return tenant, false, err
+		}
+		if health.StatusCode != http.StatusOK {
+			klog.Infof("Tenant '%s/%s' is not healthy, waiting to update pool %s", tenant.Namespace, tenant.Name, pool.Name)
+			tenant, err = c.updateTenantStatus(ctx, tenant, StatusWaitingForReadyState, totalReplicas)
+			return tenant, false, err
+		}
+
+		// the image the pools run before the first pool moves is the one to roll back to
+		lastKnownGoodImage := tenant.Status.LastKnownGoodImage
+		if !meta.IsStatusConditionTrue(tenant.Status.Conditions, miniov2.TenantConditionUpgrading) {
+			lastKnownGoodImage = currentImage
+		}
+
+		klog.Infof("Updating pool %s of Tenant '%s/%s' from %s to %s", pool.Name, tenant.Namespace, tenant.Name, currentImage, tenant.TargetImage())
+		ssCopy := ss.DeepCopy()
+		ssCopy.Spec.Template.Spec.Containers[0].Image = tenant.TargetImage()
+		if _, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Update(ctx, ssCopy, metav1.UpdateOptions{}); err != nil {
+			return tenant, false, err
+		}
+		now := metav1.Now()
+		if tenant, err = c.updateUpgradeStatus(ctx, tenant, lastKnownGoodImage, tenant.Status.RolledBackImage, &now); err != nil {
+			return tenant, false, err
+		}
+		if tenant, err = c.updateTenantStatus(ctx, tenant, StatusUpdatingMinIOVersion, totalReplicas); err != nil {
+			return tenant, false, err
+		}
+		c.recorder.Event(tenant, corev1.EventTypeNormal, UpgradeStarted, fmt.Sprintf("Updating pool %s from %s to %s", pool.Name, currentImage, tenant.TargetImage()))
+		tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionUpgrading, metav1.ConditionTrue, miniov2.ReasonUpgradeInProgress,
+			fmt.Sprintf("Updating pool %s from %s to %s", pool.Name, currentImage, tenant.TargetImage())))
+		return tenant, false, err
+	}
+
+	if meta.IsStatusConditionTrue(tenant.Status.Conditions, miniov2.TenantConditionUpgrading) {
+		var err error
+		tenant, err = c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionUpgrading, metav1.ConditionFalse, miniov2.ReasonUpgradeCompleted,
+			fmt.Sprintf("All pools updated to %s", tenant.TargetImage())))
+		if err != nil {
+			return tenant, false, err
+		}
+		c.recorder.Event(tenant, corev1.EventTypeNormal, UpgradeCompleted, fmt.Sprintf("All pools updated to %s", tenant.TargetImage()))
+		observeUpgrade(tenant, upgradeCompleted)
+	}
+	return tenant, true, nil
+}
+
+// checkUpgradeHealth watches the tenant health during the health window that follows an upgrade. If MinIO keeps
+// reporting the tenant lost write quorum, or keeps being unreachable, for upgradeRollbackFailures consecutive health
+// checks once the pools had time to come back, the upgrade is rolled back. Outside the window, the image of a healthy
+// tenant is recorded as the last known good image. It returns true if the pools were rolled back.
+func (c *Controller) checkUpgradeHealth(ctx context.Context, tenant *miniov2.Tenant, images []string) (*miniov2.Tenant, bool, error) {
+	if !tenant.InUpgradeHealthWindow() {
+		if tenant.Status.HealthStatus != miniov2.HealthStatusGreen || tenant.Status.LastKnownGoodImage == tenant.TargetImage() {
+			return tenant, false, nil
+		}
+		for _, image := range images {
+			if image != tenant.TargetImage() {
+				return tenant, false, nil
+			}
+		}
+		tenant, err := c.updateUpgradeStatus(ctx, tenant, tenant.TargetImage(), tenant.Status.RolledBackImage, tenant.Status.UpgradedAt)
+		return tenant, false, err
+	}
+
+	if tenant.Status.LastKnownGoodImage == "" || tenant.Status.LastKnownGoodImage == tenant.TargetImage() {
+		return tenant, false, nil
+	}
+	// nothing else may touch the tenant while it settles or between two checks, so schedule the next check
+	if settled := time.Since(tenant.Status.UpgradedAt.Time); settled < upgradeSettleTime {
+		c.enqueueTenantAfter(tenant, upgradeSettleTime-settled)
+		return tenant, false, nil
+	}
+
+	rootCAs, err := c.getTenantRootCAs(ctx, tenant)
+	if err != nil {
+		return tenant, false, err
+	}
+	// failures recorded before the last upgrade don't count
+	failures := tenant.Status.UpgradeHealthFailures
+	failedAt := tenant.Status.UpgradeHealthFailedAt
+	if failedAt == nil || failedAt.Before(tenant.Status.UpgradedAt) {
+		failures = 0
+	}
+	if failures > 0 && time.Since(failedAt.Time) < upgradeHealthCheckInterval {
+		c.enqueueTenantAfter(tenant, upgradeHealthCheckInterval-time.Since(failedAt.Time))
+		return tenant, false, nil
+	}
+
+	var reason string
+	health, err := getMinIOHealthStatusWithRetry(ctx, tenant.GetTenantServiceURL(), rootCAs, RegularMode, 0)
+	switch {
+	case err != nil:
+		reason = fmt.Sprintf("MinIO is unreachable: %v", err)
+	case health.StatusCode != http.StatusOK:
+		reason = fmt.Sprintf("MinIO reports the cluster lost write quorum (%d)", health.StatusCode)
+	default:
+		// keep checking until the window closes and the image is recorded as known good
+		c.enqueueTenantAfter(tenant, upgradeHealthCheckInterval)
+		if tenant.Status.UpgradeHealthFailures > 0 {
+			tenant, err = c.updateUpgradeHealthStatus(ctx, tenant, 0, nil)
+		}
+		return tenant, false, err
+	}
+
+	failures++
+	if failures < upgradeRollbackFailures {
+		klog.Infof("Tenant '%s/%s' failed %d of %d health checks before rolling back the upgrade: %s", tenant.Namespace, tenant.Name,
+			failures, upgradeRollbackFailures, reason)
+		c.enqueueTenantAfter(tenant, upgradeHealthCheckInterval)
+		now := metav1.Now()
+		tenant, err = c.updateUpgradeHealthStatus(ctx, tenant, failures, &now)
+		return tenant, false, err
+	}
+	tenant, err = c.rollbackUpgrade(ctx, tenant, reason)
+	return tenant, err == nil, err
+}
+
+// rollbackUpgrade moves the pool StatefulSets back to the last known good image and records the image that failed, so
+// the tenant is not upgraded to it again until `spec.image` changes.
+func (c *Controller) rollbackUpgrade(ctx context.Context, tenant *miniov2.Tenant, reason string) (*miniov2.Tenant, error) {
+	badImage := tenant.Spec.Image
+	goodImage := tenant.Status.LastKnownGoodImage
+	if goodImage == "" || goodImage == badImage {
+		return tenant, nil
+	}
+
+	for i := range tenant.Spec.Pools {
+		ss, err := c.getSSForPool(tenant, &tenant.Spec.Pools[i])
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return tenant, err
+		}
+		if ss.Spec.Template.Spec.Containers[0].Image == goodImage {
+			continue
+		}
+		klog.Infof("Rolling back pool %s of Tenant '%s/%s' to %s", tenant.Spec.Pools[i].Name, tenant.Namespace, tenant.Name, goodImage)
+		ssCopy := ss.DeepCopy()
+		ssCopy.Spec.Template.Spec.Containers[0].Image = goodImage
+		if _, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Update(ctx, ssCopy, metav1.UpdateOptions{}); err != nil {
+			return tenant, err
+		}
+	}
+
+	msg := fmt.Sprintf("Upgrade to %s failed, rolled back to %s: %s", badImage, goodImage, reason)
+	klog.Warningf("Tenant '%s/%s': %s", tenant.Namespace, tenant.Name, msg)
+	c.recorder.Event(tenant, corev1.EventTypeWarning, UpgradeRolledBack, msg)
+	observeUpgrade(tenant, upgradeRolledBack)
+
+	tenant, err := c.updateUpgradeStatus(ctx, tenant, goodImage, badImage, nil)
+	if err != nil {
+		return tenant, err
+	}
+	return c.updateTenantConditions(ctx, tenant, newTenantCondition(miniov2.TenantConditionUpgrading, metav1.ConditionFalse, miniov2.ReasonUpgradeRolledBack, msg))
+}
+
+// statefulSetRolledOut returns true once every replica of the StatefulSet runs the latest revision and is ready
+func statefulSetRolledOut(ss *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	return ss.Status.ObservedGeneration >= ss.Generation &&
+		ss.Status.CurrentRevision == ss.Status.UpdateRevision &&
+		ss.Status.UpdatedReplicas == replicas &&
+		ss.Status.ReadyReplicas == replicas
+}
@@ -54,6 +54,10 @@ func configureWebhookServer(c *Controller) *http.Server {
 	router.Methods(http.MethodPost).
 		Path(miniov2.WebhookCRDConversaion).
 		HandlerFunc(c.CRDConversionHandler)
+	// Operator metrics
+	router.Methods(http.MethodGet).
+		Path(miniov2.OperatorMetricsPath).
+		Handler(metricsHandler())
 	//.
 	//		Queries(restQueries("bucket")...)
 
@@ -0,0 +1,147 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ingresses
+
+import (
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/services"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HTTPRouteResource is the resource of the Gateway API HTTPRoutes
+var HTTPRouteResource = schema.GroupVersionResource{
+	Group:    "gateway.networking.k8s.io",
+	Version:  "v1",
+	Resource: "httproutes",
+}
+
+// NewForTenant returns the Ingress routing the MinIO and Console hostnames of the tenant to their services
+func NewForTenant(t *miniov2.Tenant) *networkingv1.Ingress {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            t.IngressName(),
+			Namespace:       t.Namespace,
+			OwnerReferences: t.OwnerRef(),
+			Labels:          miniov2.MergeMaps(t.MinIOPodLabels(), t.Spec.Ingress.Labels),
+			Annotations:     t.Spec.Ingress.Annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: t.Spec.Ingress.IngressClassName,
+		},
+	}
+
+	if hosts := t.MinIOIngressHosts(); len(hosts) > 0 {
+		minioSvc := services.NewClusterIPForMinIO(t)
+		for _, host := range hosts {
+			ingress.Spec.Rules = append(ingress.Spec.Rules, ingressRule(host, minioSvc))
+		}
+		if secret := t.MinIOIngressTLSSecret(); secret != "" {
+			ingress.Spec.TLS = append(ingress.Spec.TLS, networkingv1.IngressTLS{Hosts: hosts, SecretName: secret})
+		}
+	}
+
+	if host := t.ConsoleIngressHost(); host != "" {
+		ingress.Spec.Rules = append(ingress.Spec.Rules, ingressRule(host, services.NewClusterIPForConsole(t)))
+		if secret := t.ConsoleIngressTLSSecret(); secret != "" {
+			ingress.Spec.TLS = append(ingress.Spec.TLS, networkingv1.IngressTLS{Hosts: []string{host}, SecretName: secret})
+		}
+	}
+	return ingress
+}
+
+// ingressRule routes every path of the host to the port of the service
+func ingressRule(host string, svc *corev1.Service) networkingv1.IngressRule {
+	pathType := networkingv1.PathTypePrefix
+	return networkingv1.IngressRule{
+		Host: host,
+		IngressRuleValue: networkingv1.IngressRuleValue{
+			HTTP: &networkingv1.HTTPIngressRuleValue{
+				Paths: []networkingv1.HTTPIngressPath{{
+					Path:     "/",
+					PathType: &pathType,
+					Backend: networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: svc.Name,
+							Port: networkingv1.ServiceBackendPort{Number: svc.Spec.Ports[0].Port},
+						},
+					},
+				}},
+			},
+		},
+	}
+}
+
+// NewHTTPRoutesForTenant returns the Gateway API HTTPRoutes routing the MinIO and Console hostnames of the tenant to
+// their services. There is no typed client for the Gateway API, the routes are unstructured.
+func NewHTTPRoutesForTenant(t *miniov2.Tenant) []*unstructured.Unstructured {
+	var routes []*unstructured.Unstructured
+	if hosts := t.MinIOIngressHosts(); len(hosts) > 0 {
+		routes = append(routes, httpRoute(t, t.MinIOHTTPRouteName(), hosts, services.NewClusterIPForMinIO(t)))
+	}
+	if host := t.ConsoleIngressHost(); host != "" {
+		routes = append(routes, httpRoute(t, t.ConsoleHTTPRouteName(), []string{host}, services.NewClusterIPForConsole(t)))
+	}
+	return routes
+}
+
+func httpRoute(t *miniov2.Tenant, name string, hosts []string, svc *corev1.Service) *unstructured.Unstructured {
+	gateway := t.Spec.Ingress.Gateway
+	parentRef := map[string]interface{}{
+		"name": gateway.Name,
+	}
+	if gateway.Namespace != "" {
+		parentRef["namespace"] = gateway.Namespace
+	}
+	if gateway.SectionName != "" {
+		parentRef["sectionName"] = gateway.SectionName
+	}
+	hostnames := make([]interface{}, 0, len(hosts))
+	for _, host := range hosts {
+		hostnames = append(hostnames, host)
+	}
+
+	route := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": HTTPRouteResource.GroupVersion().String(),
+			"kind":       "HTTPRoute",
+			"spec": map[string]interface{}{
+				"parentRefs": []interface{}{parentRef},
+				"hostnames":  hostnames,
+				"rules": []interface{}{
+					map[string]interface{}{
+						"backendRefs": []interface{}{
+							map[string]interface{}{
+								"name": svc.Name,
+								"port": int64(svc.Spec.Ports[0].Port),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	route.SetName(name)
+	route.SetNamespace(t.Namespace)
+	route.SetOwnerReferences(t.OwnerRef())
+	route.SetLabels(miniov2.MergeMaps(t.MinIOPodLabels(), t.Spec.Ingress.Labels))
+	route.SetAnnotations(t.Spec.Ingress.Annotations)
+	return route
+}
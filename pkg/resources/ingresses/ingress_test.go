@@ -0,0 +1,105 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ingresses
+
+import (
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTenant(ingress *miniov2.IngressConfig) *miniov2.Tenant {
+	return &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Spec: miniov2.TenantSpec{
+			S3:      &miniov2.S3Features{BucketDNS: true},
+			Console: &miniov2.ConsoleConfiguration{},
+			Ingress: ingress,
+			ExternalCertSecret: []*miniov2.LocalCertificateReference{
+				{Name: "minio-tls", Type: "kubernetes.io/tls"},
+			},
+		},
+		Status: miniov2.TenantStatus{MinIOServiceName: "tenant"},
+	}
+}
+
+func TestNewForTenant(t *testing.T) {
+	tenant := newTenant(&miniov2.IngressConfig{
+		MinIOHost:        "minio.example.com",
+		ConsoleHost:      "console.example.com",
+		ConsoleTLSSecret: &corev1.LocalObjectReference{Name: "console-tls"},
+	})
+	ingress := NewForTenant(tenant)
+
+	if ingress.Name != "tenant-ingress" || ingress.Namespace != "ns" {
+		t.Errorf("expected the Ingress ns/tenant-ingress, got %s/%s", ingress.Namespace, ingress.Name)
+	}
+	expectedRules := []struct {
+		host    string
+		service string
+	}{
+		{"minio.example.com", tenant.MinIOCIServiceName()},
+		{"*.minio.example.com", tenant.MinIOCIServiceName()},
+		{"console.example.com", tenant.ConsoleCIServiceName()},
+	}
+	if len(ingress.Spec.Rules) != len(expectedRules) {
+		t.Fatalf("expected %d rules, got %d", len(expectedRules), len(ingress.Spec.Rules))
+	}
+	for i, expected := range expectedRules {
+		rule := ingress.Spec.Rules[i]
+		backend := rule.HTTP.Paths[0].Backend.Service
+		if rule.Host != expected.host || backend.Name != expected.service {
+			t.Errorf("expected %s to be routed to %s, got %s routed to %s", expected.host, expected.service, rule.Host, backend.Name)
+		}
+	}
+	if len(ingress.Spec.TLS) != 2 {
+		t.Fatalf("expected TLS for the MinIO and Console hostnames, got %v", ingress.Spec.TLS)
+	}
+	if ingress.Spec.TLS[0].SecretName != "minio-tls" || len(ingress.Spec.TLS[0].Hosts) != 2 {
+		t.Errorf("expected the MinIO hostnames to use the external certificate, got %v", ingress.Spec.TLS[0])
+	}
+	if ingress.Spec.TLS[1].SecretName != "console-tls" || ingress.Spec.TLS[1].Hosts[0] != "console.example.com" {
+		t.Errorf("expected the Console hostname to use console-tls, got %v", ingress.Spec.TLS[1])
+	}
+}
+
+func TestNewHTTPRoutesForTenant(t *testing.T) {
+	tenant := newTenant(&miniov2.IngressConfig{
+		MinIOHost: "minio.example.com",
+		Gateway:   &miniov2.GatewayReference{Name: "gateway", Namespace: "gateways"},
+	})
+	routes := NewHTTPRoutesForTenant(tenant)
+	if len(routes) != 1 {
+		t.Fatalf("expected a route for the MinIO hostnames only, got %d routes", len(routes))
+	}
+
+	route := routes[0]
+	if route.GetName() != tenant.MinIOHTTPRouteName() || route.GetKind() != "HTTPRoute" {
+		t.Errorf("expected the HTTPRoute %s, got the %s %s", tenant.MinIOHTTPRouteName(), route.GetKind(), route.GetName())
+	}
+	hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	if len(hostnames) != 2 || hostnames[0] != "minio.example.com" || hostnames[1] != "*.minio.example.com" {
+		t.Errorf("expected the MinIO hostnames, got %v", hostnames)
+	}
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if len(parentRefs) != 1 || parentRefs[0].(map[string]interface{})["namespace"] != "gateways" {
+		t.Errorf("expected the route to be attached to gateways/gateway, got %v", parentRefs)
+	}
+}
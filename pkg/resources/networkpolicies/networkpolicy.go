@@ -0,0 +1,109 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package networkpolicies
+
+import (
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// namespaceNameLabel is set by Kubernetes on every namespace to the name of the namespace
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// NewForTenant returns the NetworkPolicies isolating the pods of the tenant
+func NewForTenant(t *miniov2.Tenant) []*networkingv1.NetworkPolicy {
+	policies := []*networkingv1.NetworkPolicy{NewForMinIO(t)}
+	if t.HasKESEnabled() {
+		policies = append(policies, NewForKES(t))
+	}
+	if t.HasLogEnabled() {
+		policies = append(policies, NewForLogDB(t))
+	}
+	return policies
+}
+
+// NewForMinIO returns the NetworkPolicy allowing traffic to the MinIO pods from the MinIO pods of the same tenant,
+// its Console and Prometheus, the Operator and the clients listed in `spec.networkPolicy`
+func NewForMinIO(t *miniov2.Tenant) *networkingv1.NetworkPolicy {
+	peers := []networkingv1.NetworkPolicyPeer{
+		podPeer(t.MinIOPodLabels()),
+		namespacePeer(miniov2.GetNSFromFile()),
+	}
+	if t.HasConsoleEnabled() {
+		peers = append(peers, podPeer(t.ConsolePodLabels()))
+	}
+	if t.HasPrometheusEnabled() {
+		peers = append(peers, podPeer(t.PrometheusPodLabels()))
+	}
+	for _, namespace := range t.Spec.NetworkPolicy.ClientNamespaces {
+		peers = append(peers, namespacePeer(namespace))
+	}
+	for _, cidr := range t.Spec.NetworkPolicy.ClientCIDRs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+	return newPolicy(t, t.Name, t.MinIOPodLabels(), miniov2.MinIOPort, peers)
+}
+
+// NewForKES returns the NetworkPolicy allowing only the MinIO pods of the tenant and the KES pods, including the Job
+// creating the MinIO key, to reach KES
+func NewForKES(t *miniov2.Tenant) *networkingv1.NetworkPolicy {
+	return newPolicy(t, t.KESStatefulSetName(), t.KESPodLabels(), miniov2.KESPort,
+		[]networkingv1.NetworkPolicyPeer{podPeer(t.MinIOPodLabels()), podPeer(t.KESPodLabels())})
+}
+
+// NewForLogDB returns the NetworkPolicy allowing only the Log Search API of the tenant to reach the Log Postgres server
+func NewForLogDB(t *miniov2.Tenant) *networkingv1.NetworkPolicy {
+	return newPolicy(t, t.LogStatefulsetName(), t.LogPgPodLabels(), miniov2.LogPgPort,
+		[]networkingv1.NetworkPolicyPeer{podPeer(t.LogSearchAPIPodLabels())})
+}
+
+// newPolicy returns a NetworkPolicy isolating the selected pods, only reachable on the port from the peers
+func newPolicy(t *miniov2.Tenant, name string, podLabels map[string]string, port int, peers []networkingv1.NetworkPolicyPeer) *networkingv1.NetworkPolicy {
+	protocol := corev1.ProtocolTCP
+	policyPort := intstr.FromInt(port)
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       t.Namespace,
+			OwnerReferences: t.OwnerRef(),
+			Labels:          t.MinIOPodLabels(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podLabels},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &policyPort}},
+				From:  peers,
+			}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+}
+
+// podPeer selects the pods of the namespace of the policy
+func podPeer(podLabels map[string]string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: podLabels}}
+}
+
+// namespacePeer selects all the pods of the namespace
+func namespacePeer(namespace string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{
+		MatchLabels: map[string]string{namespaceNameLabel: namespace},
+	}}
+}
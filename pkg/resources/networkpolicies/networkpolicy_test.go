@@ -0,0 +1,59 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package networkpolicies
+
+import (
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/resources/jobs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestNewForTenant(t *testing.T) {
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "ns"},
+		Spec: miniov2.TenantSpec{
+			KES:           &miniov2.KESConfig{},
+			NetworkPolicy: &miniov2.NetworkPolicyConfig{ClientNamespaces: []string{"apps"}, ClientCIDRs: []string{"10.0.0.0/8"}},
+		},
+	}
+	policies := NewForTenant(tenant)
+	if len(policies) != 2 {
+		t.Fatalf("expected the MinIO and KES policies, got %d policies", len(policies))
+	}
+
+	minio := policies[0].Spec.Ingress[0]
+	// the MinIO pods, the Operator namespace and both clients
+	if len(minio.From) != 4 {
+		t.Errorf("expected 4 peers allowed to reach MinIO, got %d", len(minio.From))
+	}
+	if minio.Ports[0].Port.IntValue() != miniov2.MinIOPort {
+		t.Errorf("expected MinIO to be reachable on port %d, got %s", miniov2.MinIOPort, minio.Ports[0].Port.String())
+	}
+
+	kes := policies[1].Spec.Ingress[0]
+	// the MinIO pods and the KES pods, the Job creating the MinIO key runs with the KES pod labels
+	if len(kes.From) != 2 || kes.From[0].PodSelector.MatchLabels[miniov2.TenantLabel] != tenant.Name {
+		t.Errorf("expected KES to be reachable only from the MinIO and KES pods, got %v", kes.From)
+	}
+	keyJob := jobs.NewForKES(tenant)
+	if selector := kes.From[1].PodSelector; !labels.SelectorFromSet(selector.MatchLabels).Matches(labels.Set(keyJob.Spec.Template.Labels)) {
+		t.Errorf("expected KES to be reachable from the key Job pods labeled %v, got %v", keyJob.Spec.Template.Labels, selector)
+	}
+}
@@ -0,0 +1,59 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package poddisruptionbudgets
+
+import (
+	"fmt"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// NewForPool returns the PodDisruptionBudget of the pool running on the StatefulSet, allowing as many of its pods to
+// be evicted at once as the erasure sets of the pool can lose without losing write quorum. The pods are selected by
+// name, as the pods of a pool being migrated share their labels with the pods of the StatefulSet it is migrated to.
+func NewForPool(t *miniov2.Tenant, pool *miniov2.Pool, ssName string) *policyv1beta1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(int(t.PoolMaxUnavailable(pool)))
+	labels := t.MinIOPodLabels()
+	labels[miniov2.PoolLabel] = pool.Name
+	podNames := make([]string, pool.Servers)
+	for i := range podNames {
+		podNames[i] = fmt.Sprintf("%s-%d", ssName, i)
+	}
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ssName,
+			Namespace:       t.Namespace,
+			OwnerReferences: t.OwnerRef(),
+			Labels:          labels,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: t.MinIOPodLabels(),
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      appsv1.StatefulSetPodNameLabel,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   podNames,
+				}},
+			},
+		},
+	}
+}
@@ -69,11 +69,48 @@ func NewClusterIPForMinIO(t *miniov2.Tenant) *corev1.Service {
 	}
 
 	// check if the service is meant to be exposed
-	if t.Spec.ExposeServices != nil && t.Spec.ExposeServices.MinIO {
+	exposeService(svc, t.MinIOServiceExposure())
+
+	return svc
+}
+
+// exposeService renders the exposure of the service outside of the cluster
+func exposeService(svc *corev1.Service, exposure *miniov2.ServiceExposure) {
+	if exposure == nil {
+		return
+	}
+	svc.Spec.Type = exposure.Type
+	if svc.Spec.Type == "" {
 		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
 	}
+	if svc.Spec.Type == corev1.ServiceTypeClusterIP {
+		return
+	}
+	svc.Spec.Ports[0].NodePort = exposure.NodePort
+	svc.Spec.ExternalTrafficPolicy = exposure.ExternalTrafficPolicy
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		svc.Spec.LoadBalancerSourceRanges = exposure.LoadBalancerSourceRanges
+		svc.Spec.LoadBalancerIP = exposure.LoadBalancerIP
+	}
+}
 
-	return svc
+// LoadBalancerClass returns the load balancer class the service is exposed with. The class is not part of the
+// Service type of the Kubernetes API version the Operator is built with, it has to be set when creating the service.
+func LoadBalancerClass(t *miniov2.Tenant, svc *corev1.Service) string {
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return ""
+	}
+	var exposure *miniov2.ServiceExposure
+	switch svc.Name {
+	case t.MinIOCIServiceName():
+		exposure = t.MinIOServiceExposure()
+	case t.ConsoleCIServiceName():
+		exposure = t.ConsoleServiceExposure()
+	}
+	if exposure == nil {
+		return ""
+	}
+	return exposure.LoadBalancerClass
 }
 
 // ServiceForBucket will return a external name based service
@@ -119,6 +156,8 @@ func NewHeadlessForMinIO(t *miniov2.Tenant) *corev1.Service {
 			Selector:  t.MinIOPodLabels(),
 			Type:      corev1.ServiceTypeClusterIP,
 			ClusterIP: corev1.ClusterIPNone,
+			// the servers must resolve each other before they are ready, MinIO is only ready once they form a cluster
+			PublishNotReadyAddresses: true,
 		},
 	}
 
@@ -217,9 +256,7 @@ func NewClusterIPForConsole(t *miniov2.Tenant) *corev1.Service {
 		},
 	}
 	// check if the service is meant to be exposed
-	if t.Spec.ExposeServices != nil && t.Spec.ExposeServices.Console {
-		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
-	}
+	exposeService(svc, t.ConsoleServiceExposure())
 
 	return svc
 }
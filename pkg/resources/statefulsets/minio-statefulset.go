@@ -28,6 +28,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Returns the MinIO environment variables set in configuration.
@@ -53,7 +54,7 @@ func minioEnvironmentVars(t *miniov2.Tenant, wsSecret *v1.Secret, hostsTemplate
 			ValueFrom: &corev1.EnvVarSource{
 				SecretKeyRef: &corev1.SecretKeySelector{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: miniov2.WebhookSecret,
+						Name: t.WebhookSecretName(),
 					},
 					Key: miniov2.WebhookMinIOArgs,
 				},
@@ -69,15 +70,20 @@ func minioEnvironmentVars(t *miniov2.Tenant, wsSecret *v1.Secret, hostsTemplate
 
 	// Enable Bucket DNS only if asked for by default turned off
 	if t.S3BucketDNS() {
+		domains := []string{t.MinIOBucketBaseDomain()}
+		// buckets are also reachable as subdomains of the MinIO hostname routed from outside of the cluster
+		if t.Spec.Ingress != nil && t.Spec.Ingress.MinIOHost != "" {
+			domains = append(domains, t.Spec.Ingress.MinIOHost)
+		}
 		envVars = append(envVars, corev1.EnvVar{
 			Name:  "MINIO_DOMAIN",
-			Value: t.MinIOBucketBaseDomain(),
+			Value: strings.Join(domains, ","),
 		}, corev1.EnvVar{
 			Name: miniov2.WebhookMinIOBucket,
 			ValueFrom: &corev1.EnvVarSource{
 				SecretKeyRef: &corev1.SecretKeySelector{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: miniov2.WebhookSecret,
+						Name: t.WebhookSecretName(),
 					},
 					Key: miniov2.WebhookMinIOArgs,
 				},
@@ -225,7 +231,7 @@ func poolMinioServerContainer(t *miniov2.Tenant, wsSecret *v1.Secret, pool *mini
 
 	return corev1.Container{
 		Name:  miniov2.MinIOServerName,
-		Image: t.Spec.Image,
+		Image: t.TargetImage(),
 		Ports: []corev1.ContainerPort{
 			{
 				ContainerPort: miniov2.MinIOPort,
@@ -236,18 +242,72 @@ func poolMinioServerContainer(t *miniov2.Tenant, wsSecret *v1.Secret, pool *mini
 		Args:            args,
 		Env:             minioEnvironmentVars(t, wsSecret, hostsTemplate, opVersion),
 		Resources:       pool.Resources,
+		LivenessProbe:   MinIOLivenessProbe(t, pool),
+		ReadinessProbe:  MinIOReadinessProbe(t, pool),
+		StartupProbe:    MinIOStartupProbe(t, pool),
+		Lifecycle:       pool.Lifecycle,
+	}
+}
+
+// MinIOLivenessProbe returns the liveness probe of the MinIO container of a Pool, checking the liveness of MinIO
+// unless the pool sets its own probe.
+func MinIOLivenessProbe(t *miniov2.Tenant, pool *miniov2.Pool) *corev1.Probe {
+	if pool.LivenessProbe != nil {
+		return pool.LivenessProbe
+	}
+	return minioHealthProbe(t, miniov2.MinIOLivenessPath, 20, 3)
+}
+
+// MinIOReadinessProbe returns the readiness probe of the MinIO container of a Pool, checking the readiness of MinIO
+// unless the pool sets its own probe.
+func MinIOReadinessProbe(t *miniov2.Tenant, pool *miniov2.Pool) *corev1.Probe {
+	if pool.ReadinessProbe != nil {
+		return pool.ReadinessProbe
+	}
+	return minioHealthProbe(t, miniov2.MinIOReadinessPath, 20, 3)
+}
+
+// MinIOStartupProbe returns the startup probe of the MinIO container of a Pool, giving MinIO 5 minutes to start
+// unless the pool sets its own probe.
+func MinIOStartupProbe(t *miniov2.Tenant, pool *miniov2.Pool) *corev1.Probe {
+	if pool.StartupProbe != nil {
+		return pool.StartupProbe
+	}
+	return minioHealthProbe(t, miniov2.MinIOLivenessPath, 10, 30)
+}
+
+// minioHealthProbe builds a probe of a MinIO health check, with every field Kubernetes would default set so the
+// probe can be compared with the one of a deployed statefulset.
+func minioHealthProbe(t *miniov2.Tenant, path string, period, failureThreshold int32) *corev1.Probe {
+	scheme := corev1.URISchemeHTTP
+	if t.TLS() {
+		scheme = corev1.URISchemeHTTPS
+	}
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   path,
+				Port:   intstr.FromInt(miniov2.MinIOPort),
+				Scheme: scheme,
+			},
+		},
+		TimeoutSeconds:   10,
+		PeriodSeconds:    period,
+		SuccessThreshold: 1,
+		FailureThreshold: failureThreshold,
 	}
 }
 
 // GetContainerArgs returns the arguments that the MinIO container receives
 func GetContainerArgs(t *miniov2.Tenant, hostsTemplate string) []string {
 	var args []string
-	if len(t.Spec.Pools) == 1 && t.Spec.Pools[0].Servers == 1 {
+	pools := t.ServerPools()
+	if len(pools) == 1 && pools[0].Servers == 1 {
 		// to run in standalone mode we must pass the path
-		args = append(args, t.VolumePathForPool(&t.Spec.Pools[0]))
+		args = append(args, t.VolumePathForPool(&pools[0]))
 	} else {
 		for index, endpoint := range t.MinIOEndpoints(hostsTemplate) {
-			args = append(args, fmt.Sprintf("%s%s", endpoint, t.VolumePathForPool(&t.Spec.Pools[index])))
+			args = append(args, fmt.Sprintf("%s%s", endpoint, t.VolumePathForPool(&pools[index])))
 		}
 	}
 	return args
@@ -259,6 +319,21 @@ func minioPoolTolerations(z *miniov2.Pool) []corev1.Toleration {
 	return append(tolerations, z.Tolerations...)
 }
 
+// PoolTopologySpreadConstraints builds the topology spread constraints for a Pool, spreading the pods of zone aware pools evenly across zones.
+func PoolTopologySpreadConstraints(t *miniov2.Tenant, z *miniov2.Pool) []corev1.TopologySpreadConstraint {
+	var constraints []corev1.TopologySpreadConstraint
+	constraints = append(constraints, z.TopologySpreadConstraints...)
+	if z.ZoneAware != nil {
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       z.ZoneTopologyKey(),
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     ContainerMatchLabels(t, z),
+		})
+	}
+	return constraints
+}
+
 // Builds the security context for a Pool
 func minioSecurityContext(pool *miniov2.Pool) *corev1.PodSecurityContext {
 	var securityContext = corev1.PodSecurityContext{}
@@ -428,12 +503,11 @@ func NewPool(t *miniov2.Tenant, wsSecret *v1.Secret, pool *miniov2.Pool, service
 
 	if operatorTLS {
 		// Mount Operator TLS certificate to MinIO ~/cert/CAs
-		operatorTLSSecretName := "operator-tls"
 		podVolumeSources = append(podVolumeSources, []corev1.VolumeProjection{
 			{
 				Secret: &corev1.SecretProjection{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: operatorTLSSecretName,
+						Name: t.OperatorTLSSecretName(),
 					},
 					Items: []corev1.KeyToPath{
 						{Key: "public.crt", Path: "CAs/operator.crt"},
@@ -506,7 +580,7 @@ func NewPool(t *miniov2.Tenant, wsSecret *v1.Secret, pool *miniov2.Pool, service
 
 	ssMeta := metav1.ObjectMeta{
 		Namespace: t.Namespace,
-		Name:      t.PoolStatefulsetName(pool),
+		Name:      t.MinIOStatefulSetNameForPool(pool),
 		OwnerReferences: []metav1.OwnerReference{
 			*metav1.NewControllerRef(t, schema.GroupVersionKind{
 				Group:   miniov2.SchemeGroupVersion.Group,
@@ -565,6 +639,9 @@ func NewPool(t *miniov2.Tenant, wsSecret *v1.Secret, pool *miniov2.Pool, service
 		},
 	}
 
+	ss.Spec.Template.Spec.TopologySpreadConstraints = PoolTopologySpreadConstraints(t, pool)
+	ss.Spec.Template.Spec.InitContainers = pool.InitContainers
+
 	// Address issue https://github.com/kubernetes/kubernetes/issues/85332
 	if t.Spec.ImagePullSecret.Name != "" {
 		ss.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{t.Spec.ImagePullSecret}